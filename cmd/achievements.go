@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const achievementProgressBarWidth = 20
+
+var achievementsCmd = &cobra.Command{
+	Use:   "achievements",
+	Short: "List earned and pending achievements",
+	Long: `List earned achievements and the progress towards pending ones
+Example usage:
+
+streakr achievements
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		achievements, err := service.ListAchievements(cmd.Context())
+		if err != nil {
+			return err
+		}
+		earned := make([]types.AchievementProgress, 0)
+		pending := make([]types.AchievementProgress, 0)
+		for _, achievement := range achievements {
+			if achievement.Unlocked {
+				earned = append(earned, achievement)
+			} else {
+				pending = append(pending, achievement)
+			}
+		}
+
+		fmt.Fprintf(os.Stdout, "Earned (%d)\n", len(earned))
+		for _, achievement := range earned {
+			fmt.Fprintf(os.Stdout, "  🏆 %-28s %s\n", achievementLabel(achievement), achievement.UnlockedAt.Local().Format("2006-01-02"))
+		}
+		fmt.Fprintf(os.Stdout, "\nPending (%d)\n", len(pending))
+		for _, achievement := range pending {
+			fmt.Fprintf(
+				os.Stdout,
+				"  %-31s %s %d/%d\n",
+				achievementLabel(achievement),
+				tui.RenderProgressBar(achievement.Current, achievement.Target, achievementProgressBarWidth),
+				achievement.Current,
+				achievement.Target,
+			)
+		}
+		return nil
+	},
+}
+
+func achievementLabel(achievement types.AchievementProgress) string {
+	if achievement.HabitName == "" {
+		return achievement.Title
+	}
+	return fmt.Sprintf("%s (%s)", achievement.Title, achievement.HabitName)
+}
+
+func init() {
+	rootCmd.AddCommand(achievementsCmd)
+	achievementsCmd.InitDefaultHelpFlag()
+	achievementsCmd.Flags().Lookup("help").Shorthand = ""
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/Atharva21/streakr/internal/service"
 	"github.com/Atharva21/streakr/internal/store"
 	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -14,34 +16,29 @@ var addCmd = &cobra.Command{
 	Use:   "add",
 	Short: "Add a new habit to track",
 	Long: `Add will add new habit and start tracking the streaks.
-Specify name of the habit in single word followed by streakr add
+Specify name of the habit followed by streakr add, names can contain spaces and emoji.
+Commands match habits on the name's slug: lowercase with spaces turned into -
 
 Few examples:
 streakr add run --description "morning run 5kms"
 streakr add read --description "read 5 pages of any book"
 streakr add smoking --type quit
+streakr add run --tag health,morning
+streakr add "Morning Walk 🚶"     # matched as morning-walk
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
-			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
-		}
-		if len(args) > 1 {
-			return &se.StreakrError{TerminalMsg: "habit name should not be more than 1 word"}
-		}
-
-		habitName := strings.TrimSpace(args[0])
+		habitName := habitNameArg(args)
 		if habitName == "" {
 			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
 		}
-		if len(habitName) > 20 {
-			return &se.StreakrError{TerminalMsg: "habit name cannot exceed 20 characters"}
+		if util.DisplayWidth(habitName) > util.MaxHabitNameWidth {
+			return &se.StreakrError{TerminalMsg: "habit name cannot be wider than 20 characters, emoji count as 2"}
 		}
-		habitName = strings.ToLower(habitName)
 
 		description, _ := cmd.Flags().GetString("description")
 		habitType, _ := cmd.Flags().GetString("type")
 
-		if len(description) > 200 {
+		if utf8.RuneCountInString(description) > 200 {
 			return &se.StreakrError{TerminalMsg: "description cannot exceed 200 characters"}
 		}
 
@@ -53,7 +50,23 @@ streakr add smoking --type quit
 			return &se.StreakrError{TerminalMsg: fmt.Sprintf("type must be either '%s' or '%s'", store.HabitTypeImprove, store.HabitTypeQuit)}
 		}
 
-		return service.AddHabit(cmd.Context(), habitName, description, habitType)
+		tagsStr, _ := cmd.Flags().GetString("tag")
+		var tags []string
+		if strings.TrimSpace(tagsStr) != "" {
+			var err error
+			tags, err = service.NormalizeTags(strings.Split(tagsStr, ","))
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := service.AddHabit(cmd.Context(), habitName, description, habitType); err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		return service.TagHabit(cmd.Context(), habitName, tags)
 	},
 }
 
@@ -62,5 +75,6 @@ func init() {
 	addCmd.InitDefaultHelpFlag()
 	addCmd.Flags().Lookup("help").Shorthand = ""
 	addCmd.PersistentFlags().StringP("description", "d", "", "description of the habit")
+	addCmd.PersistentFlags().String("tag", "", "comma seperated tags to group the habit under")
 	addCmd.PersistentFlags().StringP("type", "t", "", fmt.Sprintf("type of the habit (%s, %s) defaults to %s if unspecified", store.HabitTypeImprove, store.HabitTypeQuit, store.HabitTypeImprove))
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import "strings"
+
+// Every command reads habit names the same way: its arguments are joined with spaces, so display names
+// need no quotes (streakr stats Morning Walk), and commands taking several habits split them on , only
+// (streakr log Morning Walk,run).
+
+// habitNameArg joins args into a single habit name.
+func habitNameArg(args []string) string {
+	return strings.TrimSpace(strings.Join(args, " "))
+}
+
+// habitNameListArg joins args and splits them into habit names on , the names may be empty.
+func habitNameListArg(args []string) []string {
+	names := strings.Split(strings.Join(args, " "), ",")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+	}
+	return names
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHabitNameArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		habit string
+		list  []string
+	}{
+		{"single word", []string{"run"}, "run", []string{"run"}},
+		{"display name", []string{"Morning", "Walk"}, "Morning Walk", []string{"Morning Walk"}},
+		{"quoted display name", []string{"Morning Walk 🚶"}, "Morning Walk 🚶", []string{"Morning Walk 🚶"}},
+		{"list", []string{"read,run"}, "read,run", []string{"read", "run"}},
+		{"list with display names", []string{"Morning", "Walk,", "run"}, "Morning Walk, run", []string{"Morning Walk", "run"}},
+		{"empty entry", []string{"run,"}, "run,", []string{"run", ""}},
+		{"no args", nil, "", []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.habit, habitNameArg(tt.args))
+			assert.Equal(t, tt.list, habitNameListArg(tt.args))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <habitA>,<habitB>",
+	Short: "Compare two habits day by day to see if they go together",
+	Long: `Compare puts the calendars of two habits side by side and, over the days both were tracked,
+shows how often they were done together, how likely one is done when the other was done or missed,
+and their phi coefficient, from -1 (never on the same day) to 1 (always on the same day).
+A quit habit counts as done on its clean days. The habits are seperated by , like for log.
+
+Example usage:
+
+streakr compare gym,sleep
+streakr compare gym,sleep --range 6m
+streakr compare gym,sleep --range q3
+streakr compare gym,sleep --from 2026-01 --to 2026-03
+`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(habitNameListArg(args)) != 2 {
+			return errors.New("specify two , separated habits, e.g. streakr compare gym,sleep")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := habitNameListArg(args)
+		habitA, habitB := names[0], names[1]
+		if habitA == "" || habitB == "" {
+			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
+		}
+		rangeStr, _ := cmd.Flags().GetString("range")
+		fromStr, _ := cmd.Flags().GetString("from")
+		toStr, _ := cmd.Flags().GetString("to")
+		weekStart := config.GetStreakrConfig().Settings.GetWeekStart()
+		var start, end time.Time
+		var err error
+		if fromStr != "" || toStr != "" {
+			if cmd.Flags().Changed("range") {
+				return &se.StreakrError{TerminalMsg: "use either --range or --from/--to"}
+			}
+			start, end, err = util.ParseFromTo(fromStr, toStr, time.Now(), weekStart)
+		} else {
+			start, end, err = util.ParseDateRange(rangeStr, time.Now(), weekStart)
+		}
+		if err != nil {
+			return &se.StreakrError{TerminalMsg: err.Error()}
+		}
+		comparison, err := service.CompareHabits(cmd.Context(), habitA, habitB, start, end)
+		if err != nil {
+			return err
+		}
+		return tui.RenderCompareView(cmd.OutOrStdout(), comparison)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.InitDefaultHelpFlag()
+	compareCmd.Flags().Lookup("help").Shorthand = ""
+	compareCmd.Flags().String("range", "90d", "days to compare, e.g. 90d, 12w, 6m, 1y, q3 or last-month")
+	compareCmd.Flags().String("from", "", "start of the days to compare, a date or range like --range")
+	compareCmd.Flags().String("to", "", "end of the days to compare, a date or range like --range")
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/streakr"
+	"github.com/spf13/cobra"
+)
+
+// bootstrapForCompletion opens the store read only without ever prompting for a passphrase,
+// see streakr.BootstrapForCompletion.
+func bootstrapForCompletion(cmd *cobra.Command) error {
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	profile, _ := cmd.Flags().GetString("profile")
+	return streakr.BootstrapForCompletion(config.Options{DataDir: dataDir, Profile: profile})
+}
+
+// listHabitNames returns the slugs of all habits, with @routine names too if withRoutines is set,
+// or nil when the db can't be read since completion must never fail loudly.
+func listHabitNames(cmd *cobra.Command, withRoutines bool) []string {
+	if err := bootstrapForCompletion(cmd); err != nil {
+		return nil
+	}
+	habits, err := service.ListHabits(cmd.Context())
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(habits))
+	for _, habit := range habits {
+		names = append(names, habit.Slug)
+	}
+	if withRoutines {
+		routines, err := service.ListRoutines(cmd.Context())
+		if err == nil {
+			for _, routine := range routines {
+				names = append(names, service.RoutinePrefix+routine.Name)
+			}
+		}
+	}
+	return names
+}
+
+// completeHabitName completes a single habit name as the first argument.
+func completeHabitName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return listHabitNames(cmd, false), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeHabitNameList completes the last entry of a , separated list of habit names,
+// skipping names already in the list. With routines set, @routine names are offered too.
+func completeHabitNameList(withRoutines bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		prefix := ""
+		if idx := strings.LastIndex(toComplete, ","); idx != -1 {
+			prefix = toComplete[:idx+1]
+		}
+		alreadyListed := make(map[string]bool)
+		for _, name := range strings.Split(prefix, ",") {
+			alreadyListed[strings.TrimSpace(name)] = true
+		}
+
+		candidates := listHabitNames(cmd, withRoutines)
+		completions := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			if !alreadyListed[candidate] {
+				completions = append(completions, prefix+candidate)
+			}
+		}
+		// no space so another habit can be appended after a ,
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+func init() {
+	logCmd.ValidArgsFunction = completeHabitNameList(true)
+	deleteCmd.ValidArgsFunction = completeHabitNameList(false)
+	statsCmd.ValidArgsFunction = completeHabitName
+	costCmd.ValidArgsFunction = completeHabitName
+	goalAddCmd.ValidArgsFunction = completeHabitName
+	tagAddCmd.ValidArgsFunction = completeHabitName
+	tagRemoveCmd.ValidArgsFunction = completeHabitName
+	remindSetCmd.ValidArgsFunction = completeHabitName
+	remindClearCmd.ValidArgsFunction = completeHabitName
+	routineCreateCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// the first argument is the new routine's name
+		if len(args) != 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeHabitNameList(false)(cmd, nil, toComplete)
+	}
+}
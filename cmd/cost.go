@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Set the cost and time of one occurrence of a quit habit",
+	Long: `Set what one occurrence of a quit habit costs, streakr stats <habit> then shows
+the money and time saved on clean days. Without flags the current values are printed.
+Examples:
+ streakr cost smoking --money 0.5 --time 5m
+ streakr cost junkfood --money 4
+ streakr cost smoking --money 0 --time 0   # clear both`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habitName := strings.ToLower(habitNameArg(args))
+		if habitName == "" {
+			return &se.StreakrError{TerminalMsg: "specify the name of a quit habit"}
+		}
+		habit, err := service.GetHabitByName(cmd.Context(), habitName)
+		if err != nil {
+			return err
+		}
+		money := habit.CostPerOccurrence.Float64
+		minutes := habit.MinutesPerOccurrence.Int64
+		if !cmd.Flags().Changed("money") && !cmd.Flags().Changed("time") {
+			fmt.Fprintf(os.Stdout, "money: %.2f\ntime: %s\n", money, tui.FormatMinutes(minutes))
+			return nil
+		}
+		if cmd.Flags().Changed("money") {
+			money, _ = cmd.Flags().GetFloat64("money")
+		}
+		if cmd.Flags().Changed("time") {
+			timeStr, _ := cmd.Flags().GetString("time")
+			minutes, err = parseMinutes(timeStr)
+			if err != nil {
+				return err
+			}
+		}
+		return service.SetHabitCosts(cmd.Context(), habitName, money, minutes)
+	},
+}
+
+// parseMinutes reads a go duration like 5m or 1h30m, a bare number is taken as minutes.
+func parseMinutes(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+	if !strings.ContainsAny(value, "hms") {
+		value += "m"
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid time '%s': use a duration like 5m or 1h30m", value)}
+	}
+	return int64(math.Round(duration.Minutes())), nil
+}
+
+func init() {
+	rootCmd.AddCommand(costCmd)
+	costCmd.InitDefaultHelpFlag()
+	costCmd.Flags().Lookup("help").Shorthand = ""
+	costCmd.Flags().Float64("money", 0, "money spent on one occurrence")
+	costCmd.Flags().String("time", "", "time spent on one occurrence, e.g. 5m or 1h30m")
+}
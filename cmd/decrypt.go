@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Turn an encrypted habit database back into a plain one",
+	Long: `Decrypt writes the encrypted habit database back to a plain SQLite file,
+removing the encrypted copy and its backup.
+Example usage:
+
+streakr decrypt
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !store.IsEncrypted() {
+			return &se.StreakrError{TerminalMsg: "the database is not encrypted"}
+		}
+		if err := store.DecryptStore(); err != nil {
+			return err
+		}
+		appConfig := config.GetStreakrConfig()
+		fmt.Fprintf(os.Stdout, "🔓 database decrypted to %s\n", filepath.Join(appConfig.DataDir, appConfig.StoreName))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(decryptCmd)
+	decryptCmd.InitDefaultHelpFlag()
+	decryptCmd.Flags().Lookup("help").Shorthand = ""
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Atharva21/streakr/internal/service"
 	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -21,20 +22,14 @@ streakr delete gym,water,smoke,run
 			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
 		}
 
-		queries := strings.Split(args[0], ",")
-		if len(args) > 1 {
-			queries = append(queries, args[1:]...)
-		}
-		if len(queries) == 0 {
-			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
-		}
+		queries := habitNameListArg(args)
 		for i, query := range queries {
 			queries[i] = strings.ToLower(strings.TrimSpace(query))
 			if queries[i] == "" {
 				return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
 			}
-			if len(queries[i]) > 20 {
-				return &se.StreakrError{TerminalMsg: "habit name cannot be > 20 chars"}
+			if util.DisplayWidth(queries[i]) > util.MaxHabitNameWidth {
+				return &se.StreakrError{TerminalMsg: "habit name cannot be wider than 20 characters, emoji count as 2"}
 			}
 		}
 
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the habit database with a passphrase",
+	Long: `Encrypt replaces the habit database with an encrypted copy (XChaCha20-Poly1305 with a key
+derived from your passphrase using argon2id). From then on streakr asks for the passphrase,
+or reads it from STREAKR_PASSPHRASE or the key file in STREAKR_KEY_FILE / encryption.key_file.
+The previous encrypted copy is kept next to it as a backup, encrypted with the same key.
+
+There is no way to recover the data if the passphrase is lost.
+
+Example usage:
+
+streakr encrypt
+STREAKR_KEY_FILE=~/.streakr-key streakr encrypt
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if store.IsEncrypted() {
+			return &se.StreakrError{TerminalMsg: "the database is already encrypted"}
+		}
+		if store.IsText() {
+			return &se.StreakrError{TerminalMsg: "text storage can't be encrypted, run `streakr storage migrate --to sqlite` first"}
+		}
+		passphrase, err := config.GetPassphrase(true)
+		if err != nil {
+			return &se.StreakrError{TerminalMsg: err.Error(), Err: err}
+		}
+		if err = store.EncryptStore(passphrase); err != nil {
+			return err
+		}
+		appConfig := config.GetStreakrConfig()
+		fmt.Fprintf(
+			os.Stdout,
+			"🔒 database encrypted to %s, the passphrase cannot be recovered so keep it safe\n",
+			filepath.Join(appConfig.DataDir, appConfig.StoreName+store.EncryptedSuffix),
+		)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(encryptCmd)
+	encryptCmd.InitDefaultHelpFlag()
+	encryptCmd.Flags().Lookup("help").Shorthand = ""
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/Atharva21/streakr/internal/shutdown"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// errorOutput is the json form of an error written with --error-format json.
+type errorOutput struct {
+	Error    string   `json:"error"`
+	Kind     string   `json:"kind"`
+	ExitCode int      `json:"exit_code"`
+	Causes   []string `json:"causes,omitempty"`
+}
+
+// errorCauses lists the messages of the errors wrapped by err, skipping repeats of the message before.
+func errorCauses(err error) []string {
+	causes := make([]string, 0)
+	previous := err.Error()
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		if msg := cause.Error(); msg != previous {
+			causes = append(causes, msg)
+			previous = msg
+		}
+	}
+	return causes
+}
+
+// usageError marks an error of cobra about the command line as a validation error that shows the usage.
+func usageError(err error) error {
+	return &se.StreakrError{TerminalMsg: err.Error(), Err: err, Kind: se.KindValidation, ShowUsage: true}
+}
+
+// markUsageErrors makes cobra's errors about flags and arguments of cmd and its subcommands usage errors,
+// see usageError. Unknown commands are caught by Execute.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return usageError(err)
+	})
+	if validateArgs := cmd.Args; validateArgs != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validateArgs(cmd, args); err != nil {
+				return usageError(err)
+			}
+			return nil
+		}
+	}
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
+	}
+}
+
+// isUnknownCommandError matches the error cobra returns for a command that doesn't exist.
+func isUnknownCommandError(err error) bool {
+	return strings.HasPrefix(err.Error(), "unknown command ")
+}
+
+// writeError reports err on w as text or json, with the wrapped errors if verbose, and returns the exit code for it.
+// Errors without a message for the terminal are unexpected, they are logged and reported as internal errors.
+func writeError(w io.Writer, err error, format string, verbose bool) int {
+	var streakrErr *se.StreakrError
+	isStreakrErr := errors.As(err, &streakrErr)
+	kind := store.ClassifyError(err)
+	if kind == se.KindHabitsRemaining {
+		// only the exit code tells, status already printed its line
+		return kind.ExitCode()
+	}
+
+	msg := err.Error()
+	if (!isStreakrErr || streakrErr.TerminalMsg == "") && kind != se.KindLocked {
+		slog.Error(err.Error())
+		msg = fmt.Sprintf("An unexpected error occurred. Please check the logs at %s for more details.", util.LogFilePath())
+		if verbose {
+			msg = "An unexpected error occurred: " + err.Error()
+		}
+	}
+	if kind == se.KindLocked && (!isStreakrErr || streakrErr.TerminalMsg == "") {
+		msg = "the database is locked by another streakr, try again in a moment"
+	}
+	causes := errorCauses(err)
+	if !verbose {
+		causes = nil
+	}
+
+	if format == errorFormatJSON {
+		json.NewEncoder(w).Encode(errorOutput{Error: msg, Kind: kind.String(), ExitCode: kind.ExitCode(), Causes: causes})
+		return kind.ExitCode()
+	}
+	fmt.Fprintln(w, msg)
+	for _, cause := range causes {
+		fmt.Fprintf(w, "  caused by: %s\n", cause)
+	}
+	if isStreakrErr && streakrErr.ShowUsage {
+		rootCmd.SetOut(w)
+		rootCmd.Usage()
+	}
+	return kind.ExitCode()
+}
+
+func exitWithError(err error) {
+	format, _ := rootCmd.PersistentFlags().GetString("error-format")
+	verbose, _ := rootCmd.PersistentFlags().GetBool("verbose")
+	shutdown.GracefulShutdown(writeError(os.Stderr, err, format, verbose))
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		exitCode int
+		contains string
+		usage    bool
+	}{
+		{"plain", errors.New("could not open a new TTY"), 1, "An unexpected error occurred", false},
+		{"without message", &se.StreakrError{Err: errors.New("disk on fire")}, 1, "An unexpected error occurred", false},
+		{"validation", &se.StreakrError{TerminalMsg: "habit name cannot be empty"}, 2, "habit name cannot be empty", false},
+		{"usage", usageError(errors.New("unknown flag: --bogus")), 2, "unknown flag: --bogus", true},
+		{"not found", &se.StreakrError{TerminalMsg: "No habit with name x", Kind: se.KindNotFound}, 3, "No habit with name x", false},
+		{"habits remaining", &se.StreakrError{Err: errors.New("2 habits remaining today"), Kind: se.KindHabitsRemaining}, 7, "", false},
+		{"busy", fmt.Errorf("log: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), 5, "the database is locked by another streakr", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.Equal(t, tt.exitCode, writeError(&out, tt.err, errorFormatText, false))
+			assert.Contains(t, out.String(), tt.contains)
+			assert.Equal(t, tt.usage, bytes.Contains(out.Bytes(), []byte("Usage:")))
+
+			out.Reset()
+			assert.Equal(t, tt.exitCode, writeError(&out, tt.err, errorFormatJSON, false))
+			if tt.contains == "" {
+				assert.Empty(t, out.String())
+				return
+			}
+			var written errorOutput
+			require.NoError(t, json.Unmarshal(out.Bytes(), &written))
+			assert.Equal(t, tt.exitCode, written.ExitCode)
+			assert.Contains(t, written.Error, tt.contains)
+		})
+	}
+}
+
+func TestExecute_UsageErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	for _, args := range [][]string{
+		{"bogus"},
+		{"version", "--bogus"},
+		{"compare", "gym"},
+		{"report", "extra"},
+	} {
+		rootCmd.SetArgs(args)
+		err := execute(context.Background())
+		require.Error(t, err, args)
+		assert.Equal(t, se.KindValidation, se.KindOf(err), args)
+		var streakrErr *se.StreakrError
+		require.ErrorAs(t, err, &streakrErr)
+		assert.True(t, streakrErr.ShowUsage, args)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/ics"
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+const exportFormatICS = "ics"
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export habits for use in other apps",
+	Long: `Export writes the streaks of all habits, or of one with --habit, to stdout.
+With --format ics every streak of an improve habit becomes an all-day calendar event and every
+slip-up of a quit habit a one day event. Events keep their ids across exports, so importing
+again updates them instead of adding duplicates.
+
+Example usage:
+
+streakr export --format ics > streakr.ics
+streakr export --format ics --habit running -o running.ics
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		habitName, _ := cmd.Flags().GetString("habit")
+		outputPath, _ := cmd.Flags().GetString("output")
+		if format != exportFormatICS {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("unsupported format %q, supported formats: %s", format, exportFormatICS)}
+		}
+
+		habitName = strings.TrimSpace(habitName)
+		events, err := service.GetCalendarEvents(cmd.Context(), habitName)
+		if err != nil {
+			return err
+		}
+		calendarName := "streakr"
+		if habitName != "" {
+			calendarName += ": " + habitName
+		}
+
+		out := os.Stdout
+		if outputPath != "" {
+			if out, err = os.Create(outputPath); err != nil {
+				return &se.StreakrError{TerminalMsg: fmt.Sprintf("could not create %s: %s", outputPath, err.Error()), Err: err}
+			}
+			defer out.Close()
+		}
+		return ics.Write(out, calendarName, events, time.Now())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("format", "f", exportFormatICS, "export format, only ics for now")
+	exportCmd.Flags().String("habit", "", "only export this habit")
+	exportCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	exportCmd.InitDefaultHelpFlag()
+	exportCmd.Flags().Lookup("help").Shorthand = ""
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+const goalProgressBarWidth = 20
+
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Set goals with deadlines for habits",
+	Long: `Goals give a habit an end point, a goal is one of
+ total_days: complete the habit on this many days
+ streak:     reach a streak of this many consecutive days
+ rate:       complete the habit on this percent of the days
+Examples:
+ streakr goal add running --target 100 --from 2026-01-01 --by 2026-12-31
+ streakr goal add smoking --kind streak --target 30 --by 2026-12-01
+ streakr goal add reading --kind rate --target 80 --by 2026-06-30
+ streakr goal list
+ streakr goal rm 2`,
+}
+
+func parseGoalDate(flag, value string) (time.Time, error) {
+	date, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return date, &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid --%s, must be YYYY-MM-DD", flag)}
+	}
+	return date, nil
+}
+
+var goalAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a goal to a habit",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habitName := strings.ToLower(habitNameArg(args))
+		if habitName == "" {
+			return &se.StreakrError{TerminalMsg: "specify the habit name to add a goal to"}
+		}
+		kind, _ := cmd.Flags().GetString("kind")
+		target, _ := cmd.Flags().GetInt64("target")
+		deadlineStr, _ := cmd.Flags().GetString("by")
+		fromStr, _ := cmd.Flags().GetString("from")
+		if deadlineStr == "" {
+			return &se.StreakrError{TerminalMsg: "specify a deadline with --by YYYY-MM-DD"}
+		}
+		deadline, err := parseGoalDate("by", deadlineStr)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		if fromStr != "" {
+			start, err = parseGoalDate("from", fromStr)
+			if err != nil {
+				return err
+			}
+		}
+		id, err := service.AddGoal(cmd.Context(), habitName, strings.ToLower(kind), target, start, deadline)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "added goal #%d\n", id)
+		return nil
+	},
+}
+
+var goalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List goals with their progress",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		goals, err := service.ListGoalProgress(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, progress := range goals {
+			fmt.Fprintf(
+				os.Stdout,
+				"#%-3d %-20s %s\n     %s %d/%d  %s\n",
+				progress.Goal.ID,
+				progress.HabitName,
+				tui.FormatGoalTarget(progress.Goal),
+				tui.RenderProgressBar(progress.Current, progress.Required, goalProgressBarWidth),
+				progress.Current,
+				progress.Required,
+				tui.FormatGoalPace(progress),
+			)
+		}
+		return nil
+	},
+}
+
+var goalRmCmd = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove goals by id",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return &se.StreakrError{TerminalMsg: "specify the goal ids to remove, see streakr goal list"}
+		}
+		for _, arg := range args {
+			id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+			if err != nil {
+				return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid goal id %s", arg)}
+			}
+			if err = service.DeleteGoal(cmd.Context(), id); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(goalCmd)
+	goalCmd.InitDefaultHelpFlag()
+	goalCmd.Flags().Lookup("help").Shorthand = ""
+	goalCmd.AddCommand(goalAddCmd)
+	goalCmd.AddCommand(goalListCmd)
+	goalCmd.AddCommand(goalRmCmd)
+	goalAddCmd.Flags().StringP("kind", "k", store.GoalKindTotalDays, "goal kind: total_days, streak or rate")
+	goalAddCmd.Flags().Int64("target", 0, "days for total_days and streak goals, percent for rate goals")
+	goalAddCmd.Flags().String("by", "", "deadline of the goal as YYYY-MM-DD")
+	goalAddCmd.Flags().String("from", "", "first day counted towards the goal as YYYY-MM-DD, defaults to today")
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/Atharva21/streakr/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -12,9 +14,12 @@ var listCmd = &cobra.Command{
 Example usage:
 
 streakr list
+streakr list --tag health
 `,
+	Annotations: map[string]string{readOnlyStoreAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return tui.RenderListView(cmd.Context())
+		tag, _ := cmd.Flags().GetString("tag")
+		return tui.RenderListView(cmd.Context(), strings.TrimSpace(tag))
 	},
 }
 
@@ -22,4 +27,5 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.InitDefaultHelpFlag()
 	addCmd.Flags().Lookup("help").Shorthand = ""
+	listCmd.PersistentFlags().String("tag", "", "only list habits with this tag")
 }
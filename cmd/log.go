@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/Atharva21/streakr/internal/service"
 	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -19,34 +22,61 @@ We can also log multiple habits at once seperated by ,
 Examples:
  streakr log run
  streakr log read,run,gym,youtube
+ streakr log Morning Walk,run
+ streakr log --tag morning
+ streakr log @morning
+ streakr log smoking --count 3
  
-This updates your current streak.`,
+This updates your current streak.
+--tag only logs the improve habits of the tag, quit habits are always logged by name.
+For quit habits --count records how many times you slipped up, logging again the same day adds to it.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
+		tag, _ := cmd.Flags().GetString("tag")
+		tag = strings.TrimSpace(tag)
+		if len(args) == 0 && tag == "" {
 			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
 		}
 
-		habitNames := strings.Split(args[0], ",")
-		if len(args) > 1 {
-			habitNames = append(habitNames, args[1:]...)
+		habitNames := make([]string, 0)
+		if len(args) > 0 {
+			habitNames = habitNameListArg(args)
 		}
-		if len(habitNames) == 0 {
-			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
+		habitNames, routineNames, err := expandRoutines(cmd.Context(), habitNames)
+		if err != nil {
+			return err
 		}
 		for i, habitName := range habitNames {
 			habitNames[i] = strings.ToLower(strings.TrimSpace(habitName))
 			if habitNames[i] == "" {
 				return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
 			}
-			if len(habitNames[i]) > 20 {
-				return &se.StreakrError{TerminalMsg: "habit name cannot be > 20 chars"}
+			if util.DisplayWidth(habitNames[i]) > util.MaxHabitNameWidth {
+				return &se.StreakrError{TerminalMsg: "habit name cannot be wider than 20 characters, emoji count as 2"}
+			}
+		}
+		if tag != "" {
+			taggedHabits, err := service.ListImproveHabitsByTag(cmd.Context(), tag)
+			if err != nil {
+				return err
+			}
+			for _, habit := range taggedHabits {
+				if !slices.Contains(habitNames, habit.Slug) {
+					habitNames = append(habitNames, habit.Slug)
+				}
 			}
 		}
-		allQuittingHabits, err := service.LogHabitsForToday(cmd.Context(), habitNames)
+		slipupCount, _ := cmd.Flags().GetInt64("count")
+		allQuittingHabits, err := service.LogHabitsForTodayWithCount(cmd.Context(), habitNames, slipupCount)
 		if err != nil {
 			return err
 		}
-		loggedHabitCount, totalHabitCount, err := service.GetTodaysLoggedHabitCount(cmd.Context())
+		defer printUnlockedAchievements(cmd.Context())
+		var loggedHabitCount, totalHabitCount int64
+		if tag != "" {
+			loggedHabitCount, totalHabitCount, err = service.GetTodaysLoggedHabitCountByTag(cmd.Context(), tag)
+		} else {
+			loggedHabitCount, totalHabitCount, err = service.GetTodaysLoggedHabitCount(cmd.Context())
+		}
 		if err != nil {
 			slog.Error(err.Error())
 			return nil
@@ -61,16 +91,92 @@ This updates your current streak.`,
 		}
 		fmt.Fprintf(
 			os.Stdout,
-			"✔️  logged %d/%d today\n",
+			"✔️  logged %d/%d today%s\n",
 			loggedHabitCount,
 			totalHabitCount,
+			getRoutineSummary(cmd.Context(), habitNames, routineNames),
 		)
 		return nil
 	},
 }
 
+// expandRoutines replaces @routine arguments with the routine's habits, dropping duplicates.
+// It also returns the names of the expanded routines.
+func expandRoutines(appContext context.Context, names []string) ([]string, []string, error) {
+	habitNames := make([]string, 0, len(names))
+	routineNames := make([]string, 0)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		members := []string{name}
+		if routineName, ok := strings.CutPrefix(name, service.RoutinePrefix); ok {
+			var err error
+			members, err = service.ExpandRoutine(appContext, routineName)
+			if err != nil {
+				return nil, nil, err
+			}
+			routineNames = append(routineNames, routineName)
+		}
+		for _, member := range members {
+			if !slices.Contains(habitNames, member) {
+				habitNames = append(habitNames, member)
+			}
+		}
+	}
+	return habitNames, routineNames, nil
+}
+
+// getRoutineSummary reports the routines touched by this log, like " · morning 2/3".
+func getRoutineSummary(appContext context.Context, loggedHabitNames, routineNames []string) string {
+	progress, err := service.GetTodaysRoutineProgress(appContext)
+	if err != nil {
+		slog.Error("failed to get routine progress", "err", err.Error())
+		return ""
+	}
+	routines, err := service.ListRoutines(appContext)
+	if err != nil {
+		slog.Error("failed to list routines", "err", err.Error())
+		return ""
+	}
+	touched := make(map[string]bool)
+	for _, routine := range routines {
+		for _, habitName := range routine.HabitNames {
+			if slices.Contains(loggedHabitNames, habitName) {
+				touched[routine.Name] = true
+			}
+		}
+	}
+	for _, routineName := range routineNames {
+		touched[routineName] = true
+	}
+	var summary strings.Builder
+	for _, routineProgress := range progress {
+		if touched[routineProgress.Name] {
+			fmt.Fprintf(&summary, " · %s %d/%d", routineProgress.Name, routineProgress.Done, routineProgress.Total)
+		}
+	}
+	return summary.String()
+}
+
+// printUnlockedAchievements celebrates achievements unlocked by this log, failures here never fail the log.
+func printUnlockedAchievements(appContext context.Context) {
+	unlocked, err := service.EvaluateAchievements(appContext)
+	if err != nil {
+		slog.Error("failed to evaluate achievements", "err", err.Error())
+		return
+	}
+	for _, achievement := range unlocked {
+		if achievement.HabitName != "" {
+			fmt.Fprintf(os.Stdout, "🏆 achievement unlocked: %s (%s)\n", achievement.Title, achievement.HabitName)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "🏆 achievement unlocked: %s\n", achievement.Title)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(logCmd)
 	logCmd.InitDefaultHelpFlag()
 	logCmd.Flags().Lookup("help").Shorthand = ""
+	logCmd.PersistentFlags().String("tag", "", "log every habit with this tag")
+	logCmd.PersistentFlags().Int64P("count", "c", 1, "number of slip-ups to record for quit habits")
 }
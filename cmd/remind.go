@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/notify"
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+const (
+	systemdUnitName     = "streakr-remind"
+	defaultRemindWindow = 15 * time.Minute
+)
+
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Send reminders for habits not logged today",
+	Long: `Remind runs in the foreground and sends a desktop notification whenever a reminder time
+of an improve habit is reached and the habit is not logged yet today.
+Use --once to check a single time window and exit, handy for cron or systemd timers.
+
+Reminder times are set per habit, habits without their own times use reminders.default_times
+from config.json. Notifications are skipped during reminders.quiet_hours.
+
+Examples:
+ streakr remind set run 07:00,19:30
+ streakr remind list
+ streakr remind
+ streakr remind --once --window 15m
+ streakr remind install --systemd`,
+	// reminders only read, so they never keep an interactive log waiting
+	Annotations: map[string]string{readOnlyStoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		once, _ := cmd.Flags().GetBool("once")
+		window, _ := cmd.Flags().GetDuration("window")
+		if window <= 0 {
+			return &se.StreakrError{TerminalMsg: "window must be a positive duration"}
+		}
+
+		if once {
+			now := time.Now()
+			return sendDueReminders(cmd.Context(), now.Add(-window), now)
+		}
+
+		lastCheck := time.Now()
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case now := <-ticker.C:
+				// a failing check, e.g. a db locked by another process, only skips this window,
+				// the daemon keeps running until it is stopped.
+				if err := store.Reload(); err != nil {
+					slog.Error("failed to reload the store", "err", err.Error())
+				} else if err := sendDueReminders(cmd.Context(), lastCheck, now); err != nil {
+					slog.Error("failed to check reminders", "from", lastCheck, "to", now, "err", err.Error())
+				}
+				lastCheck = now
+			}
+		}
+	},
+}
+
+func sendDueReminders(appContext context.Context, from, to time.Time) error {
+	reminderSettings := config.GetStreakrConfig().Settings.Reminders
+	quiet, err := service.IsInQuietHours(to, reminderSettings.QuietHours.Start, reminderSettings.QuietHours.End)
+	if err != nil {
+		return err
+	}
+	if quiet {
+		return nil
+	}
+	dueReminders, err := service.GetDueReminders(appContext, from, to, reminderSettings.DefaultTimes)
+	if err != nil {
+		return err
+	}
+	for _, dueReminder := range dueReminders {
+		body := fmt.Sprintf("%s is not logged yet today", dueReminder.Habit.Name)
+		if err := notify.Send("streakr", body, reminderSettings.Command); err != nil {
+			// a failing notifier should not stop the remaining reminders.
+			slog.Error("failed to send reminder", "habit", dueReminder.Habit.Name, "err", err.Error())
+		}
+	}
+	return nil
+}
+
+var remindSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set reminder times for a habit",
+	Long: `Set replaces the reminder times of a habit with a , seperated list of HH:MM times
+Example:
+ streakr remind set run 07:00,19:30`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return &se.StreakrError{TerminalMsg: "usage: streakr remind set <habit> <HH:MM,...>"}
+		}
+		habitName := strings.ToLower(habitNameArg(args[:len(args)-1]))
+		reminderTimes := strings.Split(args[len(args)-1], ",")
+		for i, reminderTime := range reminderTimes {
+			reminderTimes[i] = strings.TrimSpace(reminderTime)
+		}
+		return service.SetReminders(cmd.Context(), habitName, reminderTimes)
+	},
+}
+
+var remindClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all reminder times of a habit",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habitName := strings.ToLower(habitNameArg(args))
+		if habitName == "" {
+			return &se.StreakrError{TerminalMsg: "usage: streakr remind clear <habit>"}
+		}
+		return service.ClearReminders(cmd.Context(), habitName)
+	},
+}
+
+var remindListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List reminder times of all habits",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remindersByHabit, err := service.ListReminders(cmd.Context())
+		if err != nil {
+			return err
+		}
+		habitNames := make([]string, 0, len(remindersByHabit))
+		for habitName := range remindersByHabit {
+			habitNames = append(habitNames, habitName)
+		}
+		sort.Strings(habitNames)
+		for _, habitName := range habitNames {
+			fmt.Fprintf(os.Stdout, "%-20s %s\n", habitName, strings.Join(remindersByHabit[habitName], ", "))
+		}
+		defaultTimes := config.GetStreakrConfig().Settings.Reminders.DefaultTimes
+		if len(defaultTimes) > 0 {
+			fmt.Fprintf(os.Stdout, "%-20s %s\n", "(default)", strings.Join(defaultTimes, ", "))
+		}
+		return nil
+	},
+}
+
+var remindInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a systemd user timer or print a cron entry that runs reminders",
+	Long: `Install schedules 'streakr remind --once' to run every --window.
+With --systemd the unit and timer are written to the systemd user directory,
+with --cron the crontab line is printed for you to add with 'crontab -e'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useSystemd, _ := cmd.Flags().GetBool("systemd")
+		useCron, _ := cmd.Flags().GetBool("cron")
+		window, _ := cmd.Flags().GetDuration("window")
+		if useSystemd == useCron {
+			return &se.StreakrError{TerminalMsg: "specify exactly one of --systemd or --cron"}
+		}
+		minutes := int(window.Minutes())
+		if minutes < 1 || minutes > 59 {
+			return &se.StreakrError{TerminalMsg: "window must be between 1m and 59m"}
+		}
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		remindCommand := fmt.Sprintf("%s remind --once --window %dm", executable, minutes)
+
+		if useCron {
+			fmt.Fprintf(os.Stdout, "*/%d * * * * %s\n", minutes, remindCommand)
+			return nil
+		}
+
+		userConfigDir, err := os.UserConfigDir()
+		if err != nil {
+			return err
+		}
+		unitDir := filepath.Join(userConfigDir, "systemd", "user")
+		if err = os.MkdirAll(unitDir, 0700); err != nil {
+			return err
+		}
+		serviceUnit := fmt.Sprintf(`[Unit]
+Description=streakr habit reminders
+
+[Service]
+Type=oneshot
+ExecStart=%s
+`, remindCommand)
+		timer := fmt.Sprintf(`[Unit]
+Description=Run streakr habit reminders every %d minutes
+
+[Timer]
+OnCalendar=*:0/%d
+Persistent=false
+
+[Install]
+WantedBy=timers.target
+`, minutes, minutes)
+		if err = os.WriteFile(filepath.Join(unitDir, systemdUnitName+".service"), []byte(serviceUnit), 0600); err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(unitDir, systemdUnitName+".timer"), []byte(timer), 0600); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "✔️  installed %s.timer in %s\n", systemdUnitName, unitDir)
+		fmt.Fprintf(os.Stdout, "enable it with: systemctl --user daemon-reload && systemctl --user enable --now %s.timer\n", systemdUnitName)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+	remindCmd.InitDefaultHelpFlag()
+	remindCmd.Flags().Lookup("help").Shorthand = ""
+	remindCmd.Flags().Bool("once", false, "check a single window ending now and exit")
+	remindCmd.PersistentFlags().Duration("window", defaultRemindWindow, "how far back --once looks for due reminders")
+
+	remindCmd.AddCommand(remindSetCmd)
+	remindCmd.AddCommand(remindClearCmd)
+	remindCmd.AddCommand(remindListCmd)
+	remindCmd.AddCommand(remindInstallCmd)
+	remindInstallCmd.Flags().Bool("systemd", false, "install a systemd user timer")
+	remindInstallCmd.Flags().Bool("cron", false, "print a crontab entry")
+}
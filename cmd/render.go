@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/heatmap"
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	renderFormatSVG = "svg"
+	renderFormatPNG = "png"
+	maxRenderDays   = 10 * 366
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render <habit>",
+	Short: "Draw a habit's heatmap as an SVG or PNG image",
+	Long: `Render draws the days of a habit as a grid with a row per weekday and a column per week,
+like a contribution graph, for embedding in a blog or README. SVG images get month and weekday
+labels and a legend, PNG images only the grid and the legend colors.
+The format follows the extension of -o, colors default to the calendar view of the TUI.
+
+Example usage:
+
+streakr render running --range 365d -o heatmap.svg
+streakr render running --range 12w -o heatmap.png --done-color "#216e39" --background "#ffffff"
+streakr render running --range 2025 -o 2025.svg
+`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habitName := habitNameArg(args)
+		rangeStr, _ := cmd.Flags().GetString("range")
+		outputPath, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		if format == "" {
+			format = renderFormatSVG
+			if ext := strings.TrimPrefix(filepath.Ext(outputPath), "."); ext != "" {
+				format = strings.ToLower(ext)
+			}
+		}
+		if format != renderFormatSVG && format != renderFormatPNG {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid format %q, must be %s or %s", format, renderFormatSVG, renderFormatPNG)}
+		}
+		today := time.Now()
+		weekStart := config.GetStreakrConfig().Settings.GetWeekStart()
+		start, end, err := util.ParseDateRange(rangeStr, today, weekStart)
+		if err != nil {
+			return &se.StreakrError{TerminalMsg: err.Error()}
+		}
+		if util.GetDayDiff(start, end) >= maxRenderDays {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid range %q, heatmaps cover at most 10 years", rangeStr)}
+		}
+
+		// the flags override single colors of the theme
+		palette := tui.HeatmapPalette(false)
+		for flag, color := range map[string]*string{
+			"done-color":      &palette.Done,
+			"missed-color":    &palette.Missed,
+			"untracked-color": &palette.Untracked,
+			"text-color":      &palette.Text,
+			"background":      &palette.Background,
+		} {
+			if cmd.Flags().Changed(flag) {
+				*color, _ = cmd.Flags().GetString(flag)
+			}
+		}
+		if err = palette.Validate(); err != nil {
+			return &se.StreakrError{TerminalMsg: err.Error()}
+		}
+
+		stats, err := service.GetHabitStatsForRange(cmd.Context(), habitName, start, end)
+		if err != nil {
+			return err
+		}
+		h := heatmap.FromStats(stats, today, weekStart)
+		var image bytes.Buffer
+		if format == renderFormatPNG {
+			err = heatmap.WritePNG(&image, h, palette)
+		} else {
+			err = heatmap.WriteSVG(&image, h, palette, true)
+		}
+		if err != nil {
+			return err
+		}
+
+		if outputPath == "" {
+			_, err = os.Stdout.Write(image.Bytes())
+			return err
+		}
+		if err = os.WriteFile(outputPath, image.Bytes(), 0644); err != nil {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("could not write %s: %s", outputPath, err.Error()), Err: err}
+		}
+		fmt.Fprintf(os.Stdout, "🖼️ heatmap of %s written to %s\n", stats.Habit.Name, outputPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().String("range", "365d", "days to draw, e.g. 90d, 12w, 6m, 1y, 2025, q3 or this-year")
+	renderCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	renderCmd.Flags().String("format", "", "svg or png, defaults to the extension of -o or svg")
+	renderCmd.Flags().String("done-color", "", "color of done days, the theme's by default")
+	renderCmd.Flags().String("missed-color", "", "color of missed days, the theme's by default")
+	renderCmd.Flags().String("untracked-color", "", "color of days before the habit was created and after today, the theme's by default")
+	renderCmd.Flags().String("text-color", "", "color of labels, the theme's by default")
+	renderCmd.Flags().String("background", "", "background color, transparent if empty")
+	renderCmd.InitDefaultHelpFlag()
+	renderCmd.Flags().Lookup("help").Shorthand = ""
+}
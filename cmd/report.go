@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/report"
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+const reportTemplatesDirName = "templates"
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write a markdown or html report of the week, month or year",
+	Long: `Report summarizes every habit over the current week, month or year: days done, completion,
+the longest streak compared with the previous period, the best and worst habits and a heatmap.
+
+The built-in templates can be replaced by report.md.tmpl or report.html.tmpl in the templates
+directory of the streakr config dir (text/template and html/template syntax).
+
+Example usage:
+
+streakr report
+streakr report --period month --format html -o report.html
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		period, _ := cmd.Flags().GetString("period")
+		format, _ := cmd.Flags().GetString("format")
+		outputPath, _ := cmd.Flags().GetString("output")
+		if format != report.FormatMarkdown && format != report.FormatHTML {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid format %q, must be %s or %s", format, report.FormatMarkdown, report.FormatHTML)}
+		}
+
+		habitReport, err := service.GetReport(cmd.Context(), period, config.GetStreakrConfig().Settings.GetWeekStart())
+		if err != nil {
+			return &se.StreakrError{TerminalMsg: err.Error(), Err: err}
+		}
+
+		out := os.Stdout
+		if outputPath != "" {
+			if out, err = os.Create(outputPath); err != nil {
+				return &se.StreakrError{TerminalMsg: fmt.Sprintf("could not create %s: %s", outputPath, err.Error()), Err: err}
+			}
+			defer out.Close()
+		}
+		templatesDir := filepath.Join(config.GetStreakrConfig().ConfigRootDir, reportTemplatesDirName)
+		// the html report has a white background
+		palette := tui.HeatmapPalette(true)
+		if err = report.Render(out, habitReport, format, templatesDir, palette); err != nil {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("could not render the report: %s", err.Error()), Err: err}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringP("period", "p", service.ReportPeriodWeek, "week, month or year")
+	reportCmd.Flags().StringP("format", "f", report.FormatMarkdown, "md or html")
+	reportCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	reportCmd.InitDefaultHelpFlag()
+	reportCmd.Flags().Lookup("help").Shorthand = ""
+}
@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 
+	"github.com/Atharva21/streakr/internal/config"
 	"github.com/Atharva21/streakr/internal/shutdown"
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/streakr"
 	"github.com/Atharva21/streakr/internal/streakrerror"
-	"github.com/Atharva21/streakr/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -18,34 +19,113 @@ var rootCmd = &cobra.Command{
 	Long:          `streakr is a command-line tool for tracking habits and maintaining streaks...`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if format, _ := cmd.Root().PersistentFlags().GetString("error-format"); format != errorFormatText && format != errorFormatJSON {
+			return &streakrerror.StreakrError{
+				TerminalMsg: fmt.Sprintf("invalid error format %q, must be %s or %s", format, errorFormatText, errorFormatJSON),
+				Kind:        streakrerror.KindValidation,
+			}
+		}
+		if !needsBootstrap(cmd) {
+			return nil
+		}
+		if cmd.Annotations[lazyStoreAnnotation] != "" {
+			return bootstrapConfig(cmd)
+		}
+		return bootstrap(cmd)
+	},
 }
 
-func Execute(ctx context.Context) {
-	err := rootCmd.ExecuteContext(ctx)
-	if err == nil {
-		return
+// commandsWithoutData neither read habits nor settings, so they run without touching the disk.
+var commandsWithoutData = map[string]bool{
+	"help":       true,
+	"version":    true,
+	"completion": true,
+	// shell completions bootstrap when completing habit names, with the flags of the completed command
+	cobra.ShellCompRequestCmd:       true,
+	cobra.ShellCompNoDescRequestCmd: true,
+}
+
+// lazyStoreAnnotation marks commands that only need the config up front and call bootstrap
+// themselves once they know they need the store, e.g. status answering from its cache.
+const lazyStoreAnnotation = "streakr/lazy-store"
+
+// readOnlyStoreAnnotation marks commands that only read habits, they never hold a lock a concurrent
+// writer has to wait for, see store.BootstrapStore.
+const readOnlyStoreAnnotation = "streakr/read-only-store"
+
+func needsBootstrap(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if commandsWithoutData[c.Name()] {
+			return false
+		}
 	}
-	var streakrErr *streakrerror.StreakrError
-	if errors.As(err, &streakrErr) {
-		if streakrErr.ShowUsage {
-			rootCmd.Usage()
+	return true
+}
+
+// bootstrapConfig resolves the config dirs and settings from the global flags without opening the store.
+func bootstrapConfig(cmd *cobra.Command) error {
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	profile, _ := cmd.Flags().GetString("profile")
+	if err := config.BootstrapConfig(config.Options{DataDir: dataDir, Profile: profile}); err != nil {
+		return &streakrerror.StreakrError{
+			TerminalMsg: fmt.Sprintf("could not start streakr: %s", err.Error()),
+			Err:         err,
 		}
-		if streakrErr.TerminalMsg != "" {
-			util.ErrorAndExit(streakrErr.TerminalMsg)
-		} else {
-			util.ErrorAndExitGeneric(streakrErr)
+	}
+	return nil
+}
+
+// bootstrap prepares the config, logs and store from the global flags, see streakr.Bootstrap.
+// Commands with the readOnlyStoreAnnotation get a read only store.
+func bootstrap(cmd *cobra.Command) error {
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	profile, _ := cmd.Flags().GetString("profile")
+	readOnly := cmd.Annotations[readOnlyStoreAnnotation] != ""
+	err := streakr.Bootstrap(config.Options{DataDir: dataDir, Profile: profile}, readOnly)
+	var streakrErr *streakrerror.StreakrError
+	if errors.As(err, &streakrErr) && streakrErr.TerminalMsg != "" {
+		return err
+	}
+	if err != nil && store.ClassifyError(err) == streakrerror.KindLocked {
+		// sqlite being busy is reported as such, see writeError
+		return &streakrerror.StreakrError{Err: err, Kind: streakrerror.KindLocked}
+	}
+	if err != nil {
+		return &streakrerror.StreakrError{
+			TerminalMsg: fmt.Sprintf("could not start streakr: %s", err.Error()),
+			Err:         err,
+			Kind:        store.ClassifyError(err),
 		}
-	} else {
-		// This is a Cobra error - manually print it
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		rootCmd.Usage()
-		shutdown.GracefulShutdown(1)
 	}
+	return nil
+}
+
+func Execute(ctx context.Context) {
+	err := execute(ctx)
+	if err == nil {
+		// run cleanup hooks, e.g. saving an encrypted db
+		shutdown.GracefulShutdown(0)
+	}
+	exitWithError(err)
+}
+
+// execute runs the command line with cobra's errors about it marked as usage errors, see writeError.
+func execute(ctx context.Context) error {
+	markUsageErrors(rootCmd)
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil && isUnknownCommandError(err) {
+		return usageError(err)
+	}
+	return err
 }
 
 func init() {
-	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.InitDefaultHelpFlag()
+	rootCmd.PersistentFlags().String("data-dir", "", "keep the database in this directory instead of the config directory")
+	rootCmd.PersistentFlags().String("profile", "", "use a separate set of habits, settings and logs")
+	rootCmd.PersistentFlags().String("error-format", errorFormatText, "how errors are written to stderr: text or json")
+	rootCmd.PersistentFlags().Bool("verbose", false, "show the errors an error was caused by")
 	addCmd.Flags().Lookup("help").Shorthand = ""
 	rootCmd.Version = Version
 	rootCmd.SetVersionTemplate("streakr v{{.Version}}\n")
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+var routineCmd = &cobra.Command{
+	Use:   "routine",
+	Short: "Bundle habits into named routines",
+	Long: `Routines are named bundles of improve habits that can be logged at once with @name
+Examples:
+ streakr routine create morning meditate,stretch,journal
+ streakr log @morning
+ streakr routine list
+ streakr routine delete morning`,
+}
+
+var routineCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a routine from , seperated habits",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return &se.StreakrError{TerminalMsg: "specify a routine name followed by , seperated habits"}
+		}
+		habitNames := habitNameListArg(args[1:])
+		return service.CreateRoutine(cmd.Context(), args[0], habitNames)
+	},
+}
+
+var routineDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete routines, the habits themselves are kept",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return &se.StreakrError{TerminalMsg: "routine name cannot be empty"}
+		}
+		for _, name := range args {
+			if err := service.DeleteRoutine(cmd.Context(), name); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var routineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List routines with today's progress",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		routines, err := service.ListRoutines(cmd.Context())
+		if err != nil {
+			return err
+		}
+		progress, err := service.GetTodaysRoutineProgress(cmd.Context())
+		if err != nil {
+			return err
+		}
+		progressByName := make(map[string]string)
+		for _, routineProgress := range progress {
+			progressByName[routineProgress.Name] = fmt.Sprintf("%d/%d", routineProgress.Done, routineProgress.Total)
+		}
+		for _, routine := range routines {
+			fmt.Fprintf(
+				os.Stdout,
+				"@%-20s %-5s %s\n",
+				routine.Name,
+				progressByName[routine.Name],
+				strings.Join(routine.HabitNames, ", "),
+			)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(routineCmd)
+	routineCmd.InitDefaultHelpFlag()
+	routineCmd.Flags().Lookup("help").Shorthand = ""
+	routineCmd.AddCommand(routineCreateCmd)
+	routineCmd.AddCommand(routineDeleteCmd)
+	routineCmd.AddCommand(routineListCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/server"
+	"github.com/spf13/cobra"
+)
+
+const (
+	serverTokenFileName   = "server_token"
+	calendarTokenFileName = "calendar_token"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local HTTP/JSON api to log habits and read stats",
+	Long: `Serve exposes habits over a small REST api, every request needs the bearer token
+stored in the streakr config directory (created on first run).
+
+Endpoints:
+ GET    /habits
+ POST   /habits/{name}/log?date=YYYY-MM-DD   (date defaults to today)
+ DELETE /habits/{name}/log?date=YYYY-MM-DD
+ GET    /habits/{name}/stats?from=YYYY-MM-DD&to=YYYY-MM-DD
+ GET    /stats
+ GET    /calendar.ics?habit=NAME             (iCalendar feed, calendar apps pass ?token=CALENDAR_TOKEN instead)
+
+Calendar apps can't send headers, so the feed has its own read-only token in calendar_token
+next to server_token, it is only accepted by /calendar.ics.
+
+Example:
+ streakr serve --addr 127.0.0.1:7878
+ curl -H "Authorization: Bearer $(cat ~/.config/streakr/server_token)" -X POST localhost:7878/habits/run/log`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		tokenPath := filepath.Join(config.GetStreakrConfig().ConfigRootDir, serverTokenFileName)
+		token, err := server.LoadOrCreateToken(tokenPath)
+		if err != nil {
+			return err
+		}
+		feedToken, err := server.LoadOrCreateToken(filepath.Join(config.GetStreakrConfig().ConfigRootDir, calendarTokenFileName))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "serving on http://%s (token in %s)\n", addr, tokenPath)
+		fmt.Fprintf(os.Stdout, "calendar feed: http://%s/calendar.ics?token=%s\n", addr, feedToken)
+		return server.ListenAndServe(cmd.Context(), addr, server.New(token, feedToken))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.InitDefaultHelpFlag()
+	serveCmd.Flags().Lookup("help").Shorthand = ""
+	serveCmd.PersistentFlags().String("addr", "127.0.0.1:7878", "address to listen on")
+}
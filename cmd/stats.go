@@ -6,9 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Atharva21/streakr/internal/config"
 	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store/generated"
 	se "github.com/Atharva21/streakr/internal/streakrerror"
 	"github.com/Atharva21/streakr/internal/tui"
+	"github.com/Atharva21/streakr/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -18,27 +21,61 @@ var statsCmd = &cobra.Command{
 	Long: `To see current(running) streak and max streak for all habits:
   streakr stats
 
+To only see habits with a tag:
+  streakr stats --tag health
+
 To see habit wise monthly heatmap:
-  streak stats <habitname>`,
+  streak stats <habitname>
+
+To see the calendars and totals of any range:
+  streakr stats <habitname> --from 2026-03-01 --to 2026-05-31
+  streakr stats <habitname> --from last-30d
+  streakr stats <habitname> --from q3
+  streakr stats <habitname> --from 2025
+
+--from and --to take a date (2026-03-01), month (2026-03), year (2026), quarter (q3, 2026-q3),
+today, yesterday, this-week, last-week, this-month, last-month, this-year, last-year or
+last-Nd, last-Nw, last-Nm and last-Ny. Without --to a date runs up to today and
+any other range covers itself.`,
+	Annotations: map[string]string{readOnlyStoreAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			return tui.RenderOverallStats(cmd.Context())
+			tag, _ := cmd.Flags().GetString("tag")
+			return tui.RenderOverallStats(cmd.Context(), strings.TrimSpace(tag))
 		}
-		if len(args) > 1 {
-			return &se.StreakrError{TerminalMsg: "habit name should not be more than 1 word"}
-		}
-
-		habitName := strings.TrimSpace(args[0])
+		habitName := habitNameArg(args)
 		if habitName == "" {
 			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
 		}
-		if len(habitName) > 20 {
-			return &se.StreakrError{TerminalMsg: "habit name cannot exceed 20 characters"}
+		if util.DisplayWidth(habitName) > util.MaxHabitNameWidth {
+			return &se.StreakrError{TerminalMsg: "habit name cannot be wider than 20 characters, emoji count as 2"}
 		}
-		habitName = strings.ToLower(habitName)
 
+		fromStr, _ := cmd.Flags().GetString("from")
+		toStr, _ := cmd.Flags().GetString("to")
 		yearStr, _ := cmd.Flags().GetString("year")
 		monthStr, _ := cmd.Flags().GetString("month")
+		if fromStr != "" || toStr != "" {
+			if yearStr != "" || monthStr != "" {
+				return &se.StreakrError{TerminalMsg: "use either --from/--to or --month/--year"}
+			}
+			start, end, err := util.ParseFromTo(fromStr, toStr, time.Now(), config.GetStreakrConfig().Settings.GetWeekStart())
+			if err != nil {
+				return &se.StreakrError{TerminalMsg: err.Error()}
+			}
+			habit, err := getHabitInRange(cmd, habitName, start, end)
+			if err != nil {
+				return err
+			}
+			// only the tracked part of the range has anything to show
+			if util.CompareDate(start, habit.CreatedAt) == 1 {
+				start = util.GetNoonOf(habit.CreatedAt)
+			}
+			if today := util.GetNoonOf(time.Now()); util.CompareDate(end, today) == -1 {
+				end = today
+			}
+			return tui.RenderRangeStatsView(cmd.Context(), cmd.OutOrStdout(), habit, start, end)
+		}
 
 		// Validate and convert year
 		currentYear := time.Now().Year()
@@ -69,72 +106,49 @@ To see habit wise monthly heatmap:
 			}
 			month = time.Month(monthNum)
 		} else {
-			// Try parsing as month name
-			switch strings.ToLower(monthStr) {
-			case "january", "jan":
-				month = time.January
-			case "february", "feb":
-				month = time.February
-			case "march", "mar":
-				month = time.March
-			case "april", "apr":
-				month = time.April
-			case "may":
-				month = time.May
-			case "june", "jun":
-				month = time.June
-			case "july", "jul":
-				month = time.July
-			case "august", "aug":
-				month = time.August
-			case "september", "sep":
-				month = time.September
-			case "october", "oct":
-				month = time.October
-			case "november", "nov":
-				month = time.November
-			case "december", "dec":
-				month = time.December
-			default:
-				return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid month '%s': must be 1-12 or month name", monthStr)}
+			// Try parsing as month name, in the configured locale or english
+			locale := config.GetStreakrConfig().Settings.GetLocale()
+			english, _ := util.GetLocale(util.DefaultLocale)
+			var ok bool
+			if month, ok = locale.ParseMonth(monthStr); !ok {
+				if month, ok = english.ParseMonth(monthStr); !ok {
+					return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid month '%s': must be 1-12 or month name", monthStr)}
+				}
 			}
 		}
 		startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
 		endOfMonth := startOfMonth.AddDate(0, 1, -1)
-		habit, err := service.GetHabitByName(cmd.Context(), habitName)
-		if err != nil {
-			return err
-		}
-		startRange, endRange := habit.CreatedAt, time.Now()
+		habit, err := getHabitInRange(cmd, habitName, startOfMonth, endOfMonth)
 		if err != nil {
 			return err
 		}
-		// fmt.Printf("ms: %v, me: %v, rs: %v, re: %v\n", startOfMonth, endOfMonth, startRange, endRange)
-		if startOfMonth.Year() < startRange.Year() {
-			return &se.StreakrError{TerminalMsg: "Cannot get stats before habit creation date."}
-		}
-		if startOfMonth.Year() == startRange.Year() {
-			if startOfMonth.Month() < startRange.Month() {
-				return &se.StreakrError{TerminalMsg: "Cannot get stats before habit creation date."}
-			}
-		}
-		if endOfMonth.Year() > endRange.Year() {
-			return &se.StreakrError{TerminalMsg: "Cannot get stats after latest streak"}
-		}
-		if endOfMonth.Year() == endRange.Year() {
-			if endOfMonth.Month() > endRange.Month() {
-				return &se.StreakrError{TerminalMsg: "Cannot get stats after latest streak"}
-			}
-		}
 		err = tui.RenderStatsView(cmd.Context(), startOfMonth.Year(), int(startOfMonth.Month()), habit)
 		return err
 	},
 }
 
+// getHabitInRange gets the habit, provided it was tracked on at least one day from start to end.
+func getHabitInRange(cmd *cobra.Command, habitName string, start, end time.Time) (generated.Habit, error) {
+	habit, err := service.GetHabitByName(cmd.Context(), habitName)
+	if err != nil {
+		return habit, err
+	}
+	if util.CompareDate(end, habit.CreatedAt) == 1 {
+		return habit, &se.StreakrError{TerminalMsg: "Cannot get stats before habit creation date."}
+	}
+	if util.CompareDate(start, time.Now()) == -1 {
+		return habit, &se.StreakrError{TerminalMsg: "Cannot get stats after latest streak"}
+	}
+	return habit, nil
+}
+
 func init() {
 	rootCmd.AddCommand(statsCmd)
 	statsCmd.InitDefaultHelpFlag()
 	statsCmd.Flags().Lookup("help").Shorthand = ""
 	statsCmd.PersistentFlags().StringP("month", "m", "", "Specify the month to view stats of")
 	statsCmd.PersistentFlags().StringP("year", "y", "", "Specify the year to view stats of")
+	statsCmd.PersistentFlags().String("from", "", "start of the range to view stats of, e.g. 2026-03-01, last-30d, this-week, q3 or 2026")
+	statsCmd.PersistentFlags().String("to", "", "end of the range to view stats of, defaults to today for a --from date")
+	statsCmd.PersistentFlags().String("tag", "", "only show habits with this tag in overall stats")
 }
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultStatusFormat = "{{.Done}}/{{.Total}} 🔥{{.BestStreak}}"
+	statusCacheFileName = "status.json"
+)
+
+// statusCache holds the last computed summary, it is valid as long as the db is untouched
+// and we are still on the same day.
+type statusCache struct {
+	DBModTime int64
+	Date      string
+	Summary   types.StatusSummary
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print a one line summary of today's progress",
+	Long: `Status prints a one line summary of today's progress, meant for shell prompts and status bars.
+The output is a go text/template with the fields .Done, .Total, .Remaining and .BestStreak
+Exits with status 7 if there are improve habits yet to be logged today.
+
+Examples:
+ streakr status
+ streakr status --format '{{.Done}}/{{.Total}} 🔥{{.BestStreak}}'
+ streakr status --cache --format '{{.Remaining}} left'
+ streakr status --tag morning`,
+	// the cache is read before the store is opened, a hit never touches the db
+	Annotations: map[string]string{lazyStoreAnnotation: "true", readOnlyStoreAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		useCache, _ := cmd.Flags().GetBool("cache")
+		tag, _ := cmd.Flags().GetString("tag")
+		// the cache only holds the unfiltered summary
+		useCache = useCache && tag == ""
+
+		tmpl, err := template.New("status").Parse(format)
+		if err != nil {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid format: %s", err.Error())}
+		}
+
+		var summary *types.StatusSummary
+		if useCache {
+			summary = readStatusCache()
+		}
+		if summary == nil {
+			if err = bootstrap(cmd); err != nil {
+				return err
+			}
+			summary, err = service.GetStatusSummary(cmd.Context(), tag)
+			if err != nil {
+				return err
+			}
+			if useCache {
+				writeStatusCache(summary)
+			}
+		}
+
+		if err = tmpl.Execute(os.Stdout, summary); err != nil {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid format: %s", err.Error())}
+		}
+		fmt.Fprintln(os.Stdout)
+		if summary.Remaining > 0 {
+			return &se.StreakrError{
+				Err:  fmt.Errorf("%d habits remaining today", summary.Remaining),
+				Kind: se.KindHabitsRemaining,
+			}
+		}
+		return nil
+	},
+}
+
+// getDBModTime returns the latest modification time of the store of whichever backend is in use.
+func getDBModTime() (int64, error) {
+	appConfig := config.GetStreakrConfig()
+	return store.ModTime(filepath.Join(appConfig.DataDir, appConfig.StoreName), appConfig.TextDir)
+}
+
+func readStatusCache() *types.StatusSummary {
+	modTime, err := getDBModTime()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(config.GetStreakrConfig().CacheDir, statusCacheFileName))
+	if err != nil {
+		return nil
+	}
+	var cache statusCache
+	if err = json.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	if cache.DBModTime != modTime || cache.Date != time.Now().Format(time.DateOnly) {
+		return nil
+	}
+	return &cache.Summary
+}
+
+func writeStatusCache(summary *types.StatusSummary) {
+	modTime, err := getDBModTime()
+	if err != nil {
+		slog.Error("could not stat db for status cache", "err", err.Error())
+		return
+	}
+	data, err := json.Marshal(statusCache{
+		DBModTime: modTime,
+		Date:      time.Now().Format(time.DateOnly),
+		Summary:   *summary,
+	})
+	if err != nil {
+		slog.Error("could not marshal status cache", "err", err.Error())
+		return
+	}
+	err = os.WriteFile(filepath.Join(config.GetStreakrConfig().CacheDir, statusCacheFileName), data, 0600)
+	if err != nil {
+		slog.Error("could not write status cache", "err", err.Error())
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.InitDefaultHelpFlag()
+	statusCmd.Flags().Lookup("help").Shorthand = ""
+	statusCmd.PersistentFlags().StringP("format", "f", defaultStatusFormat, "go template for the status line")
+	statusCmd.PersistentFlags().Bool("cache", false, "reuse the last result while the database is unchanged")
+	statusCmd.PersistentFlags().String("tag", "", "only count habits with this tag")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+const (
+	storageSQLite = "sqlite"
+	storageText   = "text"
+)
+
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Show or change how habits are stored",
+	Long: `Storage prints the storage backend in use, either the SQLite database or plain text files.
+Text storage keeps one YAML file per habit and a ledger file per year with a line per logged day,
+meant to be diffed and committed to git. Set storage.text_dir in config.json to keep it elsewhere.
+
+Example usage:
+
+streakr storage
+streakr storage migrate --to text
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		appConfig := config.GetStreakrConfig()
+		switch {
+		case store.IsText():
+			fmt.Fprintf(os.Stdout, "text files in %s\n", appConfig.TextDir)
+		case store.IsEncrypted():
+			fmt.Fprintf(os.Stdout, "encrypted sqlite database %s\n", filepath.Join(appConfig.DataDir, appConfig.StoreName+store.EncryptedSuffix))
+		default:
+			fmt.Fprintf(os.Stdout, "sqlite database %s\n", filepath.Join(appConfig.DataDir, appConfig.StoreName))
+		}
+		return nil
+	},
+}
+
+var storageMigrateTo string
+
+var storageMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move habits between the SQLite database and plain text files",
+	Long: `Migrate moves all habits, logs, goals, routines and achievements to the other storage backend.
+Migrating to text keeps the database as streakr.db.bak, migrating back to sqlite leaves the text files as they are.
+
+Example usage:
+
+streakr storage migrate --to text
+streakr storage migrate --to sqlite
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		appConfig := config.GetStreakrConfig()
+		switch storageMigrateTo {
+		case storageText:
+			if err := store.MigrateToText(appConfig.TextDir); err != nil {
+				return &se.StreakrError{TerminalMsg: err.Error(), Err: err}
+			}
+			fmt.Fprintf(os.Stdout, "📝 habits moved to text files in %s\n", appConfig.TextDir)
+		case storageSQLite:
+			if err := store.MigrateToSQLite(); err != nil {
+				return &se.StreakrError{TerminalMsg: err.Error(), Err: err}
+			}
+			fmt.Fprintf(os.Stdout, "🗄️ habits moved to %s\n", filepath.Join(appConfig.DataDir, appConfig.StoreName))
+		default:
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("--to must be %s or %s", storageText, storageSQLite), ShowUsage: true}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(storageCmd)
+	storageCmd.InitDefaultHelpFlag()
+	storageCmd.Flags().Lookup("help").Shorthand = ""
+
+	storageCmd.AddCommand(storageMigrateCmd)
+	storageMigrateCmd.Flags().StringVar(&storageMigrateTo, "to", "", "backend to move to: text or sqlite")
+	storageMigrateCmd.InitDefaultHelpFlag()
+	storageMigrateCmd.Flags().Lookup("help").Shorthand = ""
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const syncDeviceFileName = "sync_device"
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync habits with other devices through a shared folder",
+	Long: `Sync shares habits between devices through a folder kept in sync by a tool like
+Syncthing or Dropbox, set it with sync.folder in config.json or --folder.
+
+Every device appends its changes (habits added, edited or deleted, days logged or unlogged)
+to its own log in the folder and replays the logs of the other devices. When two devices change
+the same habit or the same day of a habit, the latest change wins.
+
+Examples:
+ streakr sync
+ streakr sync --dry-run
+ streakr sync --folder ~/Sync/streakr`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		folder, _ := cmd.Flags().GetString("folder")
+		appConfig := config.GetStreakrConfig()
+		if folder == "" {
+			folder = appConfig.Settings.Sync.Folder
+		}
+		if folder == "" {
+			return &se.StreakrError{TerminalMsg: "no sync folder, set sync.folder in config.json or pass --folder"}
+		}
+		device, err := service.LoadOrCreateSyncDevice(filepath.Join(appConfig.ConfigRootDir, syncDeviceFileName))
+		if err != nil {
+			return err
+		}
+		result, err := service.Sync(cmd.Context(), folder, device, dryRun)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			fmt.Fprintf(os.Stdout, "dry run, %d incoming changes would be applied:\n", len(result.Applied))
+		} else {
+			fmt.Fprintf(os.Stdout, "⇣ applied %d incoming changes\n", len(result.Applied))
+		}
+		for _, op := range result.Applied {
+			fmt.Fprintf(os.Stdout, "  %-12s %s\n", op.Device, describeSyncOp(op))
+		}
+		if result.Overridden > 0 {
+			fmt.Fprintf(os.Stdout, "  %d older changes skipped, a later change won\n", result.Overridden)
+		}
+		if dryRun {
+			fmt.Fprintf(os.Stdout, "%d local changes to write to %s\n", result.Outgoing, result.LogPath)
+		} else {
+			fmt.Fprintf(os.Stdout, "⇡ wrote %d local changes to %s\n", result.Outgoing, result.LogPath)
+		}
+		return nil
+	},
+}
+
+func describeSyncOp(op types.SyncOp) string {
+	switch op.Op {
+	case service.SyncOpAddHabit:
+		return fmt.Sprintf("add %s habit %s", op.HabitType, op.Name)
+	case service.SyncOpDeleteHabit:
+		return fmt.Sprintf("delete habit %s", op.Habit)
+	case service.SyncOpEditHabit:
+		return fmt.Sprintf("set cost of %s to %.2f and %dm", op.Habit, op.CostPerOccurrence, op.MinutesPerOccurrence)
+	case service.SyncOpLogDay:
+		if op.Count > 1 {
+			return fmt.Sprintf("log %s on %s (%d times)", op.Habit, op.Day, op.Count)
+		}
+		return fmt.Sprintf("log %s on %s", op.Habit, op.Day)
+	case service.SyncOpUnlogDay:
+		return fmt.Sprintf("unlog %s on %s", op.Habit, op.Day)
+	default:
+		return fmt.Sprintf("%s %s", op.Op, op.Habit)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.InitDefaultHelpFlag()
+	syncCmd.Flags().Lookup("help").Shorthand = ""
+	syncCmd.Flags().Bool("dry-run", false, "show incoming changes without applying them")
+	syncCmd.Flags().String("folder", "", "shared sync folder, overrides sync.folder in config.json")
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Atharva21/streakr/internal/service"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Group habits with tags",
+	Long: `Tag habits to group them, tags can then be used to filter list, stats, log and status
+Examples:
+ streakr tag add run health,morning
+ streakr tag remove run morning
+ streakr tag list`,
+}
+
+func parseTagArgs(args []string) (string, []string, error) {
+	if len(args) < 2 {
+		return "", nil, &se.StreakrError{TerminalMsg: "specify a habit name followed by , seperated tags"}
+	}
+	habitName := strings.ToLower(habitNameArg(args[:len(args)-1]))
+	if habitName == "" {
+		return "", nil, &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
+	}
+	return habitName, strings.Split(args[len(args)-1], ","), nil
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add tags to a habit",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habitName, tags, err := parseTagArgs(args)
+		if err != nil {
+			return err
+		}
+		return service.TagHabit(cmd.Context(), habitName, tags)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove tags from a habit",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		habitName, tags, err := parseTagArgs(args)
+		if err != nil {
+			return err
+		}
+		return service.UntagHabit(cmd.Context(), habitName, tags)
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all tags and their habits",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, err := service.ListTags(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			habits, err := service.ListHabitsByTag(cmd.Context(), tag)
+			if err != nil {
+				return err
+			}
+			habitNames := make([]string, 0, len(habits))
+			for _, habit := range habits {
+				habitNames = append(habitNames, habit.Name)
+			}
+			fmt.Fprintf(os.Stdout, "#%-20s %s\n", tag, strings.Join(habitNames, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.InitDefaultHelpFlag()
+	tagCmd.Flags().Lookup("help").Shorthand = ""
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagListCmd)
+}
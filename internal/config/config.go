@@ -1,12 +1,16 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sync"
+	"time"
 
-	"github.com/Atharva21/streakr/internal/shutdown"
+	"github.com/Atharva21/streakr/internal/util"
 )
 
 var bootstrapConfigOnce sync.Once
@@ -14,11 +18,100 @@ var bootstrapConfigOnce sync.Once
 type StreakrConfig struct {
 	ConfigRootDir string
 	DataDir       string
+	CacheDir      string
+	HooksDir      string
 	LogFileDir    string
 	LogFileName   string
 	StoreName     string
+	// TextDir holds the plain text storage, used instead of the db once migrated to it.
+	TextDir  string
+	Settings Settings
 }
 
+// Settings are the user editable preferences read from config.json under ConfigRootDir.
+// Every field is optional, missing ones fall back to defaults.
+type Settings struct {
+	Reminders  ReminderSettings   `json:"reminders"`
+	Webhooks   []WebhookSettings  `json:"webhooks"`
+	Encryption EncryptionSettings `json:"encryption"`
+	Sync       SyncSettings       `json:"sync"`
+	Storage    StorageSettings    `json:"storage"`
+	Theme      ThemeSettings      `json:"theme"`
+	// WeekStart is sunday, monday or saturday, monday if empty.
+	WeekStart string `json:"week_start"`
+	// Locale names weekdays and months in the calendar, e.g. de or fr, english if empty.
+	Locale string `json:"locale"`
+}
+
+// GetWeekStart is the day weeks start on, WeekStart is validated when the settings are loaded.
+func (s Settings) GetWeekStart() time.Weekday {
+	weekStart, _ := util.ParseWeekStart(s.WeekStart)
+	return weekStart
+}
+
+// GetLocale is the locale of the calendar, Locale is validated when the settings are loaded.
+func (s Settings) GetLocale() util.Locale {
+	locale, _ := util.GetLocale(s.Locale)
+	return locale
+}
+
+type ThemeSettings struct {
+	// Name is a built-in theme, picked from the terminal background when empty or auto.
+	Name string `json:"name"`
+	// Glyphs marks calendar days with symbols as well as colors.
+	Glyphs bool        `json:"glyphs"`
+	Colors ThemeColors `json:"colors"`
+}
+
+// ThemeColors override single colors of the theme, as hex colors or ANSI color numbers.
+type ThemeColors struct {
+	Accent string `json:"accent"`
+	Done   string `json:"done"`
+	Missed string `json:"missed"`
+	Muted  string `json:"muted"`
+	Help   string `json:"help"`
+	Subtle string `json:"subtle"`
+}
+
+type StorageSettings struct {
+	// TextDir overrides where the plain text storage lives, e.g. inside a git repository.
+	TextDir string `json:"text_dir"`
+}
+
+type SyncSettings struct {
+	// Folder is shared between devices by a file sync tool, each device writes its own log in it.
+	Folder string `json:"folder"`
+}
+
+type EncryptionSettings struct {
+	// KeyFile holds the passphrase of an encrypted db, STREAKR_PASSPHRASE takes precedence over it.
+	KeyFile string `json:"key_file"`
+}
+
+type WebhookSettings struct {
+	URL string `json:"url"`
+	// Events limits delivery to these event types, all events are sent if empty.
+	Events         []string `json:"events"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+	Retries        int      `json:"retries"`
+}
+
+type ReminderSettings struct {
+	// DefaultTimes (HH:MM) apply to improve habits that have no reminder times of their own.
+	DefaultTimes []string `json:"default_times"`
+	// QuietHours suppress notifications between Start and End (HH:MM), can wrap midnight.
+	QuietHours QuietHours `json:"quiet_hours"`
+	// Command is run through `sh -c` instead of notify-send, with STREAKR_TITLE and STREAKR_BODY set.
+	Command string `json:"command"`
+}
+
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+const settingsFileName = "config.json"
+
 var streakrConfigInstance *StreakrConfig = nil
 
 func GetStreakrConfig() StreakrConfig {
@@ -28,36 +121,97 @@ func GetStreakrConfig() StreakrConfig {
 	return *streakrConfigInstance
 }
 
-func exitWithStderrGeneric(err error) {
-	fmt.Fprintf(os.Stderr, "An unexpected error occurred while bootstrapping necessary config files for streakr: %s", err.Error())
-	shutdown.GracefulShutdown(1)
+// Options come from the global command line flags and change where streakr keeps its files.
+type Options struct {
+	// DataDir holds the db instead of the data dir of the profile.
+	DataDir string
+	// Profile keeps a separate set of habits, settings and logs under profiles/<name> of the config dir.
+	Profile string
 }
 
-func BootstrapConfig() {
+var (
+	profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	bootstrapConfigErr error
+)
+
+// BootstrapConfig resolves the config dirs, creates them and reads config.json. Only the first call does anything,
+// later ones return its error.
+func BootstrapConfig(options Options) error {
 	bootstrapConfigOnce.Do(func() {
-		streakrConfigInstance = &StreakrConfig{}
-		userHomeDir, err := os.UserConfigDir()
-		if err != nil {
-			exitWithStderrGeneric(err)
+		bootstrapConfigErr = bootstrapConfig(options)
+		if bootstrapConfigErr != nil {
+			streakrConfigInstance = nil
 		}
-		streakrConfigInstance.ConfigRootDir = filepath.Join(userHomeDir, "streakr")
-		streakrConfigInstance.DataDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "data")
-		streakrConfigInstance.LogFileDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "logs")
-		streakrConfigInstance.LogFileName = "streakr.log"
-		streakrConfigInstance.StoreName = "streakr.db"
-
-		// Create necessary directories
-		err = os.MkdirAll(streakrConfigInstance.ConfigRootDir, 0700)
-		if err != nil {
-			exitWithStderrGeneric(err)
+	})
+	return bootstrapConfigErr
+}
+
+func bootstrapConfig(options Options) error {
+	streakrConfigInstance = &StreakrConfig{}
+	userHomeDir, err := os.UserConfigDir()
+	if err != nil {
+		return err
+	}
+	streakrConfigInstance.ConfigRootDir = filepath.Join(userHomeDir, "streakr")
+	if options.Profile != "" {
+		if !profileNamePattern.MatchString(options.Profile) {
+			return fmt.Errorf("invalid profile %q, use letters, digits, - and _ only", options.Profile)
 		}
-		err = os.MkdirAll(streakrConfigInstance.DataDir, 0700)
-		if err != nil {
-			exitWithStderrGeneric(err)
+		streakrConfigInstance.ConfigRootDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "profiles", options.Profile)
+	}
+	streakrConfigInstance.DataDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "data")
+	if options.DataDir != "" {
+		if streakrConfigInstance.DataDir, err = filepath.Abs(options.DataDir); err != nil {
+			return err
 		}
-		err = os.MkdirAll(streakrConfigInstance.LogFileDir, 0700)
-		if err != nil {
-			exitWithStderrGeneric(err)
+	}
+	streakrConfigInstance.CacheDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "cache")
+	streakrConfigInstance.HooksDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "hooks")
+	streakrConfigInstance.LogFileDir = filepath.Join(streakrConfigInstance.ConfigRootDir, "logs")
+	streakrConfigInstance.LogFileName = "streakr.log"
+	streakrConfigInstance.StoreName = "streakr.db"
+
+	// Create necessary directories
+	for _, dir := range []string{
+		streakrConfigInstance.ConfigRootDir,
+		streakrConfigInstance.DataDir,
+		streakrConfigInstance.CacheDir,
+		streakrConfigInstance.LogFileDir,
+	} {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return err
 		}
-	})
+	}
+
+	streakrConfigInstance.Settings, err = loadSettings(filepath.Join(streakrConfigInstance.ConfigRootDir, settingsFileName))
+	if err != nil {
+		return err
+	}
+	streakrConfigInstance.TextDir = filepath.Join(streakrConfigInstance.DataDir, "text")
+	if streakrConfigInstance.Settings.Storage.TextDir != "" {
+		streakrConfigInstance.TextDir = streakrConfigInstance.Settings.Storage.TextDir
+	}
+	return nil
+}
+
+func loadSettings(settingsPath string) (Settings, error) {
+	settings := Settings{}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return settings, nil
+		}
+		return settings, err
+	}
+	if err = json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("invalid %s: %w", settingsPath, err)
+	}
+	// every calendar, report and range has to agree on these, so a typo fails loudly instead of falling back
+	if _, err = util.ParseWeekStart(settings.WeekStart); err != nil {
+		return settings, fmt.Errorf("invalid %s: %w", settingsPath, err)
+	}
+	if _, err = util.GetLocale(settings.Locale); err != nil {
+		return settings, fmt.Errorf("invalid %s: %w", settingsPath, err)
+	}
+	return settings, nil
 }
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapConfig_Options(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { streakrConfigInstance = nil })
+	configHome, err := os.UserConfigDir()
+	require.NoError(t, err)
+	root := filepath.Join(configHome, "streakr")
+
+	require.NoError(t, bootstrapConfig(Options{}))
+	assert.Equal(t, root, GetStreakrConfig().ConfigRootDir)
+	assert.Equal(t, filepath.Join(root, "data"), GetStreakrConfig().DataDir)
+	assert.Equal(t, filepath.Join(root, "data", "text"), GetStreakrConfig().TextDir)
+	assert.DirExists(t, filepath.Join(root, "logs"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "profiles", "work"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "profiles", "work", settingsFileName), []byte(`{"week_start": "sunday"}`), 0600))
+	dataDir := filepath.Join(t.TempDir(), "habits")
+	require.NoError(t, bootstrapConfig(Options{Profile: "work", DataDir: dataDir}))
+	assert.Equal(t, filepath.Join(root, "profiles", "work"), GetStreakrConfig().ConfigRootDir)
+	assert.Equal(t, dataDir, GetStreakrConfig().DataDir)
+	assert.Equal(t, filepath.Join(dataDir, "text"), GetStreakrConfig().TextDir)
+	assert.Equal(t, "sunday", GetStreakrConfig().Settings.WeekStart)
+	assert.DirExists(t, dataDir)
+
+	for _, profile := range []string{"../work", "a/b", "work space"} {
+		assert.Error(t, bootstrapConfig(Options{Profile: profile}), profile)
+	}
+}
+
+func TestLoadSettings_Invalid(t *testing.T) {
+	settingsPath := filepath.Join(t.TempDir(), settingsFileName)
+	for _, settings := range []string{`{"week_start": "friday"}`, `{"locale": "xx"}`, `{`} {
+		require.NoError(t, os.WriteFile(settingsPath, []byte(settings), 0600))
+		_, err := loadSettings(settingsPath)
+		assert.Error(t, err, settings)
+	}
+
+	require.NoError(t, os.WriteFile(settingsPath, []byte(`{"week_start": "Sunday", "locale": "de"}`), 0600))
+	settings, err := loadSettings(settingsPath)
+	require.NoError(t, err)
+	assert.Equal(t, time.Sunday, settings.GetWeekStart())
+	assert.Equal(t, "Januar", settings.GetLocale().MonthName(time.January))
+}
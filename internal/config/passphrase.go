@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	PassphraseEnv = "STREAKR_PASSPHRASE"
+	KeyFileEnv    = "STREAKR_KEY_FILE"
+)
+
+// GetPassphrase returns the passphrase of the encrypted db from STREAKR_PASSPHRASE, the key file
+// (STREAKR_KEY_FILE or encryption.key_file in config.json) or a terminal prompt, in that order.
+// confirm asks twice when prompting, for choosing a new passphrase.
+func GetPassphrase(confirm bool) ([]byte, error) {
+	if passphrase, found, err := storedPassphrase(); found {
+		return passphrase, err
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return nil, fmt.Errorf("no passphrase for the encrypted database, set %s or %s, or run streakr in a terminal", PassphraseEnv, KeyFileEnv)
+	}
+	passphrase, err := promptPassphrase("passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if confirm {
+		again, err := promptPassphrase("repeat passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(passphrase, again) {
+			return nil, errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// GetPassphraseWithoutPrompt returns the passphrase from STREAKR_PASSPHRASE or the key file like GetPassphrase,
+// but fails instead of prompting when neither is set, for when nobody could see the prompt.
+func GetPassphraseWithoutPrompt() ([]byte, error) {
+	if passphrase, found, err := storedPassphrase(); found {
+		return passphrase, err
+	}
+	return nil, fmt.Errorf("no passphrase for the encrypted database, set %s or %s", PassphraseEnv, KeyFileEnv)
+}
+
+// storedPassphrase returns the passphrase from STREAKR_PASSPHRASE or the key file, found is false if neither is set.
+func storedPassphrase() (passphrase []byte, found bool, err error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return []byte(passphrase), true, nil
+	}
+	keyFile := os.Getenv(KeyFileEnv)
+	if keyFile == "" {
+		keyFile = GetStreakrConfig().Settings.Encryption.KeyFile
+	}
+	if keyFile == "" {
+		return nil, false, nil
+	}
+	passphrase, err = readKeyFile(keyFile)
+	return passphrase, true, err
+}
+
+func readKeyFile(keyFile string) ([]byte, error) {
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read key file: %w", err)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		slog.Warn("key file is accessible by other users", "path", keyFile, "mode", info.Mode().Perm().String())
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read key file: %w", err)
+	}
+	passphrase := bytes.TrimRight(data, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("key file %s is empty", keyFile)
+	}
+	return passphrase, nil
+}
+
+func promptPassphrase(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type EventType = string
+
+const (
+	HabitAdded      EventType = "habit.added"
+	HabitLogged     EventType = "habit.logged"
+	StreakBroken    EventType = "streak.broken"
+	StreakMilestone EventType = "streak.milestone"
+	SlipupLogged    EventType = "slipup.logged"
+	HabitUnlogged   EventType = "habit.unlogged"
+)
+
+// StreakMilestones are the streak lengths (in days) that trigger a streak.milestone event.
+var StreakMilestones = []int64{7, 14, 21, 30, 50, 100, 200, 365, 500, 1000}
+
+type HabitPayload struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	HabitType   string `json:"habit_type"`
+}
+
+// Event is what handlers receive, it is serialized as is for hooks and webhooks.
+type Event struct {
+	Type           EventType    `json:"event"`
+	Time           time.Time    `json:"time"`
+	Habit          HabitPayload `json:"habit"`
+	CurrentStreak  int64        `json:"current_streak"`
+	PreviousStreak int64        `json:"previous_streak,omitempty"`
+	Milestone      int64        `json:"milestone,omitempty"`
+}
+
+// Handler reacts to an event, returned errors are only logged.
+type Handler func(ctx context.Context, event Event) error
+
+var (
+	handlersMu sync.RWMutex
+	handlers   []Handler
+	// pending tracks deliveries of Async handlers still running in the background.
+	pending sync.WaitGroup
+)
+
+func RegisterHandler(handler Handler) {
+	if handler == nil {
+		return
+	}
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers = append(handlers, handler)
+}
+
+// ResetHandlersForTesting removes all registered handlers
+// This should ONLY be used in test code
+func ResetHandlersForTesting() {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	handlers = nil
+}
+
+// Publish runs every registered handler concurrently and waits for them to finish.
+// Handler failures are logged and never surface to the caller.
+func Publish(ctx context.Context, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	handlersMu.RLock()
+	currentHandlers := append([]Handler(nil), handlers...)
+	handlersMu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, handler := range currentHandlers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("event handler panicked", "event", event.Type, "panic", r)
+				}
+			}()
+			if err := handler(ctx, event); err != nil {
+				slog.Error("event handler failed", "event", event.Type, "err", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Async runs handler in the background so slow handlers, e.g. webhooks retrying, don't hold up
+// the command that published the event. Failures are logged, Wait lets them finish before exiting.
+func Async(handler Handler) Handler {
+	return func(ctx context.Context, event Event) error {
+		// the delivery outlives the request or command that published the event
+		ctx = context.WithoutCancel(ctx)
+		pending.Add(1)
+		go func() {
+			defer pending.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("event handler panicked", "event", event.Type, "panic", r)
+				}
+			}()
+			if err := handler(ctx, event); err != nil {
+				slog.Error("event handler failed", "event", event.Type, "err", err.Error())
+			}
+		}()
+		return nil
+	}
+}
+
+// Wait blocks until the deliveries started by Async handlers finish or timeout passes,
+// it returns false if some were still running.
+func Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
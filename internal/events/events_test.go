@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish_HandlerFailuresDoNotPropagate(t *testing.T) {
+	ResetHandlersForTesting()
+	defer ResetHandlersForTesting()
+
+	var calls atomic.Int32
+	RegisterHandler(func(ctx context.Context, event Event) error {
+		calls.Add(1)
+		return errors.New("boom")
+	})
+	RegisterHandler(func(ctx context.Context, event Event) error {
+		calls.Add(1)
+		panic("handler panic")
+	})
+	RegisterHandler(func(ctx context.Context, event Event) error {
+		calls.Add(1)
+		assert.Equal(t, HabitLogged, event.Type)
+		assert.False(t, event.Time.IsZero())
+		return nil
+	})
+
+	Publish(context.Background(), Event{Type: HabitLogged})
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var attempts atomic.Int32
+	var received Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	handler := NewWebhookHandler(WebhookConfig{URL: ts.URL, Retries: 2})
+	err := handler(context.Background(), Event{Type: StreakMilestone, Milestone: 30, Habit: HabitPayload{Name: "running"}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, int64(30), received.Milestone)
+	assert.Equal(t, "running", received.Habit.Name)
+
+	// gives up once retries are exhausted
+	attempts.Store(0)
+	handler = NewWebhookHandler(WebhookConfig{URL: ts.URL, Retries: 1})
+	assert.Error(t, handler(context.Background(), Event{Type: StreakMilestone}))
+
+	// filtered events are never sent
+	attempts.Store(0)
+	handler = NewWebhookHandler(WebhookConfig{URL: ts.URL, Events: []EventType{SlipupLogged}})
+	require.NoError(t, handler(context.Background(), Event{Type: StreakMilestone}))
+	assert.Equal(t, int32(0), attempts.Load())
+}
+
+func TestScriptHandler(t *testing.T) {
+	hooksDir := t.TempDir()
+	outputPath := filepath.Join(hooksDir, "output.json")
+	script := "#!/bin/sh\ncat > " + outputPath + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, SlipupLogged), []byte(script), 0700))
+
+	handler := NewScriptHandler(hooksDir)
+	require.NoError(t, handler(context.Background(), Event{Type: SlipupLogged, Habit: HabitPayload{Name: "smoking"}}))
+
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	var received Event
+	require.NoError(t, json.Unmarshal(data, &received))
+	assert.Equal(t, SlipupLogged, received.Type)
+	assert.Equal(t, "smoking", received.Habit.Name)
+
+	// events without a hook are ignored
+	require.NoError(t, handler(context.Background(), Event{Type: HabitAdded}))
+
+	// failing hooks report an error
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, HabitLogged), []byte("#!/bin/sh\nexit 3\n"), 0700))
+	assert.Error(t, handler(context.Background(), Event{Type: HabitLogged}))
+}
+
+func TestAsync(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	handler := Async(func(ctx context.Context, event Event) error {
+		<-release
+		calls.Add(1)
+		return errors.New("async failures are only logged")
+	})
+
+	// returns before the handler finished, even once the publisher's context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, handler(ctx, Event{Type: HabitLogged}))
+	cancel()
+	assert.False(t, Wait(10*time.Millisecond))
+	assert.Equal(t, int32(0), calls.Load())
+
+	close(release)
+	assert.True(t, Wait(time.Second))
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const (
+	defaultHookTimeout    = 10 * time.Second
+	defaultWebhookTimeout = 5 * time.Second
+	webhookRetryBackoff   = 500 * time.Millisecond
+)
+
+// NewScriptHandler runs the executable hooksDir/<event> (if present) with the event json on stdin.
+func NewScriptHandler(hooksDir string) Handler {
+	return func(ctx context.Context, event Event) error {
+		hookPath := filepath.Join(hooksDir, event.Type)
+		info, err := os.Stat(hookPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || info.Mode()&0111 == 0 {
+			return fmt.Errorf("hook %s is not executable", hookPath)
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(ctx, defaultHookTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, hookPath)
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Env = append(os.Environ(), "STREAKR_EVENT="+event.Type)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("hook %s failed: %w: %s", hookPath, err, output)
+		}
+		return nil
+	}
+}
+
+type WebhookConfig struct {
+	URL string
+	// Events to deliver, all events if empty.
+	Events  []EventType
+	Timeout time.Duration
+	Retries int
+}
+
+// NewWebhookHandler POSTs the event json to a url, retrying on network errors and non 2xx responses.
+func NewWebhookHandler(webhookConfig WebhookConfig) Handler {
+	timeout := webhookConfig.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	return func(ctx context.Context, event Event) error {
+		if len(webhookConfig.Events) > 0 && !slices.Contains(webhookConfig.Events, event.Type) {
+			return nil
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		var lastErr error
+		for attempt := 0; attempt <= webhookConfig.Retries; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(webhookRetryBackoff * time.Duration(attempt)):
+				}
+			}
+			lastErr = postWebhook(ctx, client, webhookConfig.URL, payload)
+			if lastErr == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("webhook %s failed after %d attempts: %w", webhookConfig.URL, webhookConfig.Retries+1, lastErr)
+	}
+}
+
+func postWebhook(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Package heatmap draws a habit's days as a grid with a row per weekday and a column per week,
+// as SVG or PNG.
+package heatmap
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+type State int
+
+const (
+	Untracked State = iota // before the habit was created or after today
+	Done
+	Missed
+)
+
+type Heatmap struct {
+	Title string
+	Start time.Time
+	Days  []bool
+	// TrackedFrom and Today bound the days that can be missed.
+	TrackedFrom time.Time
+	Today       time.Time
+	// WeekStart is the day of the first row.
+	WeekStart time.Weekday
+}
+
+// FromStats makes the heatmap of a habit's stats for a range, with weeks starting on weekStart.
+func FromStats(stats *types.HabitStatsForRange, today time.Time, weekStart time.Weekday) Heatmap {
+	return Heatmap{
+		Title:       stats.Habit.Name,
+		Start:       stats.RangeStart,
+		Days:        stats.Heatmap,
+		TrackedFrom: stats.Habit.CreatedAt,
+		Today:       today,
+		WeekStart:   weekStart,
+	}
+}
+
+func (h Heatmap) Day(i int) time.Time {
+	return h.Start.AddDate(0, 0, i)
+}
+
+func (h Heatmap) State(i int) State {
+	day := h.Day(i)
+	switch {
+	case h.Days[i]:
+		return Done
+	case util.CompareDate(day, h.Today) == -1 || util.CompareDate(h.TrackedFrom, day) == -1:
+		return Untracked
+	}
+	return Missed
+}
+
+// Weeks is the number of columns, the first one starts on the WeekStart before Start.
+func (h Heatmap) Weeks() int {
+	return (h.Offset() + len(h.Days) + 6) / 7
+}
+
+// Offset is the row of Start, rows start on WeekStart.
+func (h Heatmap) Offset() int {
+	return util.GetWeekdayOffset(h.Start, h.WeekStart)
+}
+
+// Weekday is the day of the week of a row.
+func (h Heatmap) Weekday(row int) time.Weekday {
+	return time.Weekday((int(h.WeekStart) + row) % 7)
+}
+
+// Cell returns the column and row of day i.
+func (h Heatmap) Cell(i int) (int, int) {
+	return (h.Offset() + i) / 7, (h.Offset() + i) % 7
+}
+
+// Palette holds hex colors (#rgb, #rrggbb or #rrggbbaa), an empty Background is transparent.
+type Palette struct {
+	Done       string
+	Missed     string
+	Untracked  string
+	Text       string
+	Background string
+}
+
+func (p Palette) Color(state State) string {
+	switch state {
+	case Done:
+		return p.Done
+	case Missed:
+		return p.Missed
+	}
+	return p.Untracked
+}
+
+func (p Palette) Validate() error {
+	for _, hex := range []string{p.Done, p.Missed, p.Untracked, p.Text} {
+		if _, err := ParseColor(hex); err != nil {
+			return err
+		}
+	}
+	if p.Background != "" {
+		_, err := ParseColor(p.Background)
+		return err
+	}
+	return nil
+}
+
+// ParseColor parses #rgb, #rrggbb and #rrggbbaa hex colors.
+func ParseColor(hex string) (color.RGBA, error) {
+	digits, ok := strings.CutPrefix(hex, "#")
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if !ok || len(digits) != 8 || err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected a hex color like #25a425", hex)
+	}
+	return color.RGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
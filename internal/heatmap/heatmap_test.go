@@ -0,0 +1,111 @@
+package heatmap
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPalette = Palette{
+	Done:      "#25a425",
+	Missed:    "#c25252",
+	Untracked: "#444444",
+	Text:      "#666666",
+}
+
+// testHeatmap covers march 2026 from a wednesday, tracked from the 2nd with today the 20th.
+func testHeatmap() Heatmap {
+	start := time.Date(2026, 2, 25, 12, 0, 0, 0, time.Local)
+	days := make([]bool, 34)
+	days[10] = true
+	return Heatmap{
+		Title:       "running",
+		Start:       start,
+		Days:        days,
+		TrackedFrom: start.AddDate(0, 0, 5),
+		Today:       start.AddDate(0, 0, 23),
+		WeekStart:   time.Monday,
+	}
+}
+
+func TestHeatmap_Layout(t *testing.T) {
+	h := testHeatmap()
+	assert.Equal(t, 2, h.Offset())
+	assert.Equal(t, 6, h.Weeks())
+	column, row := h.Cell(5)
+	assert.Equal(t, 1, column)
+	assert.Equal(t, 0, row)
+
+	assert.Equal(t, Untracked, h.State(4))
+	assert.Equal(t, Missed, h.State(5))
+	assert.Equal(t, Done, h.State(10))
+	assert.Equal(t, Missed, h.State(23))
+	assert.Equal(t, Untracked, h.State(24))
+
+	h.WeekStart = time.Sunday
+	assert.Equal(t, 3, h.Offset())
+	assert.Equal(t, time.Sunday, h.Weekday(0))
+	column, row = h.Cell(4)
+	assert.Equal(t, 1, column)
+	assert.Equal(t, 0, row)
+}
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSVG(&buf, testHeatmap(), testPalette, true))
+	svg := buf.String()
+	assert.Equal(t, 34, strings.Count(svg, "<title>"))
+	assert.Contains(t, svg, `fill="#25a425"><title>2026-03-07</title>`)
+	// february starts too late in the range to be labeled
+	assert.NotContains(t, svg, ">Feb<")
+	assert.Contains(t, svg, ">Mar<")
+	assert.Contains(t, svg, ">not tracked<")
+	assert.Contains(t, svg, ">Mon<")
+	assert.NotContains(t, svg, ">Sun<")
+
+	buf.Reset()
+	sundays := testHeatmap()
+	sundays.WeekStart = time.Sunday
+	require.NoError(t, WriteSVG(&buf, sundays, testPalette, true))
+	assert.Contains(t, buf.String(), ">Sun<")
+	assert.Contains(t, buf.String(), ">Tue<")
+	assert.NotContains(t, buf.String(), ">Mon<")
+
+	buf.Reset()
+	require.NoError(t, WriteSVG(&buf, testHeatmap(), testPalette, false))
+	assert.NotContains(t, buf.String(), "<text")
+}
+
+func TestWritePNG(t *testing.T) {
+	palette := testPalette
+	palette.Background = "#fff"
+	var buf bytes.Buffer
+	require.NoError(t, WritePNG(&buf, testHeatmap(), palette))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 6*cellStep*pngScale, img.Bounds().Dx())
+
+	// day 10 sits in the second column, fifth row
+	x, y := (1*cellStep+1)*pngScale, (5*cellStep+1)*pngScale
+	assert.Equal(t, color.RGBA{R: 0x25, G: 0xa4, B: 0x25, A: 0xff}, img.At(x, y))
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, img.At(0, (7*cellStep+2)*pngScale))
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#5d8addff")
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 0x5d, G: 0x8a, B: 0xdd, A: 0xff}, c)
+	c, err = ParseColor("#abc")
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 0xaa, G: 0xbb, B: 0xcc, A: 0xff}, c)
+	for _, invalid := range []string{"", "red", "25a425", "#25a42", "#gggggg"} {
+		_, err = ParseColor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,47 @@
+package heatmap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// pngScale doubles the SVG geometry so cells stay crisp on high density screens.
+const pngScale = 2
+
+// WritePNG draws the bare grid and its legend colors as a PNG image,
+// labels are left out since the standard library has no fonts.
+func WritePNG(w io.Writer, h Heatmap, p Palette) error {
+	colors := make(map[State]color.RGBA)
+	for _, state := range []State{Done, Missed, Untracked} {
+		c, err := ParseColor(p.Color(state))
+		if err != nil {
+			return err
+		}
+		colors[state] = c
+	}
+	width, height := h.Weeks()*cellStep, 7*cellStep+legendTop+cellSize
+	img := image.NewRGBA(image.Rect(0, 0, width*pngScale, height*pngScale))
+	if p.Background != "" {
+		background, err := ParseColor(p.Background)
+		if err != nil {
+			return err
+		}
+		draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	}
+
+	fill := func(x, y int, c color.RGBA) {
+		rect := image.Rect(x*pngScale, y*pngScale, (x+cellSize)*pngScale, (y+cellSize)*pngScale)
+		draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Over)
+	}
+	for i := range h.Days {
+		column, row := h.Cell(i)
+		fill(column*cellStep, row*cellStep, colors[h.State(i)])
+	}
+	for i, item := range legend {
+		fill(i*cellStep, 7*cellStep+legendTop, colors[item.state])
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,127 @@
+package heatmap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"time"
+)
+
+const (
+	cellSize    = 10
+	cellGap     = 2
+	cellStep    = cellSize + cellGap
+	labelsLeft  = 30
+	labelsTop   = 16
+	titleHeight = 20
+	legendTop   = 10
+	legendSize  = 24
+	fontSize    = 9
+)
+
+// WriteSVG draws the heatmap as an SVG image. With labels it also gets the title,
+// month and weekday labels and a legend, without them it is the bare grid.
+func WriteSVG(w io.Writer, h Heatmap, p Palette, labels bool) error {
+	bw := bufio.NewWriter(w)
+	left, top := 0, 0
+	width, height := h.Weeks()*cellStep, 7*cellStep
+	if labels {
+		left, top = labelsLeft, labelsTop
+		if h.Title != "" {
+			top += titleHeight
+		}
+		width = max(width+left, left+legendWidth())
+		height += top + legendTop + legendSize
+	}
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height)
+	if p.Background != "" {
+		fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`, p.Background)
+	}
+	if labels {
+		fmt.Fprintf(bw, `<g font-family="sans-serif" font-size="%d" fill="%s">`, fontSize, p.Text)
+		if h.Title != "" {
+			fmt.Fprintf(bw, `<text x="0" y="%d" font-size="%d">%s</text>`, titleHeight-6, fontSize+4, html.EscapeString(h.Title))
+		}
+		// every other row is labeled, like a contribution graph
+		for row := 0; row < 6; row += 2 {
+			fmt.Fprintf(bw, `<text x="0" y="%d">%s</text>`, top+row*cellStep+cellSize-1, h.Weekday(row).String()[:3])
+		}
+		for _, label := range monthLabels(h) {
+			fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`, left+label.column*cellStep, top-5, label.name)
+		}
+		fmt.Fprint(bw, `</g>`)
+	}
+
+	for i := range h.Days {
+		column, row := h.Cell(i)
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s</title></rect>`,
+			left+column*cellStep, top+row*cellStep, cellSize, cellSize, p.Color(h.State(i)), h.Day(i).Format(time.DateOnly))
+	}
+
+	if labels {
+		x, y := left, top+7*cellStep+legendTop
+		fmt.Fprintf(bw, `<g font-family="sans-serif" font-size="%d" fill="%s">`, fontSize, p.Text)
+		for _, item := range legend {
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"/>`, x, y, cellSize, cellSize, p.Color(item.state))
+			fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text>`, x+cellStep, y+cellSize-1, item.name)
+			x += legendItemWidth(item.name)
+		}
+		fmt.Fprint(bw, `</g>`)
+	}
+	fmt.Fprint(bw, "</svg>\n")
+	return bw.Flush()
+}
+
+var legend = []struct {
+	name  string
+	state State
+}{
+	{"done", Done},
+	{"missed", Missed},
+	{"not tracked", Untracked},
+}
+
+// legendItemWidth estimates the width of a legend square and its name in the label font.
+func legendItemWidth(name string) int {
+	return cellStep + len(name)*fontSize*2/3 + 12
+}
+
+func legendWidth() int {
+	width := 0
+	for _, item := range legend {
+		width += legendItemWidth(item.name)
+	}
+	return width
+}
+
+type monthLabel struct {
+	name   string
+	column int
+}
+
+// monthLabels puts a label over the first column of every month, dropping labels that would overlap.
+func monthLabels(h Heatmap) []monthLabel {
+	labels := make([]monthLabel, 0)
+	for i := range h.Days {
+		day := h.Day(i)
+		// a range starting late in a month leaves no room to label it
+		if (i > 0 && day.Day() != 1) || day.Day() > 14 {
+			continue
+		}
+		column, row := h.Cell(i)
+		if row > 0 {
+			// the month starts mid week, label the first full week
+			column++
+		}
+		if len(labels) > 0 && column-labels[len(labels)-1].column < 3 {
+			continue
+		}
+		if column >= h.Weeks() {
+			continue
+		}
+		labels = append(labels, monthLabel{name: day.Format("Jan"), column: column})
+	}
+	return labels
+}
@@ -0,0 +1,68 @@
+// Package ics writes calendar events in the iCalendar format (RFC 5545).
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Atharva21/streakr/internal/types"
+)
+
+const (
+	dateLayout  = "20060102"
+	stampLayout = "20060102T150405Z"
+	// maxLineOctets is the longest content line allowed before folding.
+	maxLineOctets = 75
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// Write writes events as an iCalendar named name, stamped with now.
+func Write(w io.Writer, name string, events []types.CalendarEvent, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	stamp := now.UTC().Format(stampLayout)
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//streakr//streakr//EN")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "METHOD:PUBLISH")
+	writeLine(bw, "X-WR-CALNAME:"+escapeText(name))
+	for _, event := range events {
+		start := event.Start
+		end := start.AddDate(0, 0, event.Days)
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escapeText(event.UID))
+		writeLine(bw, "DTSTAMP:"+stamp)
+		writeLine(bw, "DTSTART;VALUE=DATE:"+start.Format(dateLayout))
+		// the end of an all-day event is exclusive
+		writeLine(bw, "DTEND;VALUE=DATE:"+end.Format(dateLayout))
+		writeLine(bw, "SUMMARY:"+escapeText(event.Summary))
+		writeLine(bw, "TRANSP:TRANSPARENT")
+		writeLine(bw, "END:VEVENT")
+	}
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// writeLine ends a content line with CRLF, folding it into 75 octet lines without splitting utf-8 characters.
+func writeLine(w *bufio.Writer, line string) {
+	octets := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if octets+size > maxLineOctets {
+			w.WriteString("\r\n ")
+			// the leading space of a continuation line counts towards its length
+			octets = 1
+		}
+		w.WriteRune(r)
+		octets += size
+	}
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,41 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	events := []types.CalendarEvent{{
+		UID:     "running-20261030@streakr",
+		Summary: "🔥 running, 3 day streak",
+		Start:   time.Date(2026, 10, 30, 12, 0, 0, 0, time.Local),
+		Days:    3,
+	}}
+	var sb strings.Builder
+	require.NoError(t, Write(&sb, "streakr", events, time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC)))
+	out := sb.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, out, "UID:running-20261030@streakr\r\n")
+	assert.Contains(t, out, "DTSTAMP:20261102T083000Z\r\n")
+	// the end date is exclusive and crosses the month
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20261030\r\nDTEND;VALUE=DATE:20261102\r\n")
+	assert.Contains(t, out, "SUMMARY:🔥 running\\, 3 day streak\r\n")
+}
+
+func TestWriteLine_Folds(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, Write(&sb, strings.Repeat("é", 60), nil, time.Now()))
+	for _, line := range strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		assert.True(t, strings.ToValidUTF8(line, "") == line, "folding must not split characters")
+	}
+	assert.Contains(t, sb.String(), "\r\n é")
+}
@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Send delivers a desktop notification.
+// If command is set it is run through `sh -c` with STREAKR_TITLE and STREAKR_BODY in its environment,
+// else notify-send is used when available, falling back to a terminal bell on stdout.
+func Send(title, body, command string) error {
+	if command != "" {
+		c := exec.Command("sh", "-c", command)
+		c.Env = append(os.Environ(), "STREAKR_TITLE="+title, "STREAKR_BODY="+body)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		return c.Run()
+	}
+	if notifySend, err := exec.LookPath("notify-send"); err == nil {
+		if err = exec.Command(notifySend, "--app-name=streakr", title, body).Run(); err == nil {
+			return nil
+		}
+	}
+	_, err := fmt.Fprintf(os.Stdout, "\a%s: %s\n", title, body)
+	return err
+}
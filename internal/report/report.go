@@ -0,0 +1,153 @@
+// Package report renders habit reports with text/template for markdown and html/template for html.
+package report
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/heatmap"
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/types"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// TemplateName is the file a template is read from, both in the built-in templates and in a custom template dir.
+func TemplateName(format string) string {
+	return fmt.Sprintf("report.%s.tmpl", format)
+}
+
+// Render writes the report in format, with heatmap images in palette. A template named TemplateName(format)
+// in customDir replaces the built-in one.
+func Render(w io.Writer, report *types.Report, format, customDir string, palette heatmap.Palette) error {
+	if format != FormatMarkdown && format != FormatHTML {
+		return fmt.Errorf("invalid format %q, must be %s or %s", format, FormatMarkdown, FormatHTML)
+	}
+	name := TemplateName(format)
+	source, err := os.ReadFile(filepath.Join(customDir, name))
+	if errors.Is(err, os.ErrNotExist) || customDir == "" {
+		source, err = templatesFS.ReadFile("templates/" + name)
+	}
+	if err != nil {
+		return err
+	}
+
+	funcs := map[string]any{
+		"date":        func(t time.Time) string { return t.Format("Jan 2, 2006") },
+		"periodName":  periodName,
+		"pct":         func(value float64) string { return fmt.Sprintf("%.0f%%", value) },
+		"signedPct":   func(value float64) string { return fmt.Sprintf("%+.0f%%", value) },
+		"signed":      func(value int) string { return fmt.Sprintf("%+d", value) },
+		"textHeatmap": textHeatmap,
+		"cell":        func(text string) string { return strings.ReplaceAll(text, "|", `\|`) },
+	}
+	if format == FormatHTML {
+		funcs["svgHeatmap"] = func(report *types.Report, habit types.HabitReport) (htmltemplate.HTML, error) {
+			svg, err := svgHeatmap(report, habit, palette)
+			return htmltemplate.HTML(svg), err
+		}
+		tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(source))
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, report)
+	}
+	funcs["svgHeatmap"] = func(report *types.Report, habit types.HabitReport) (string, error) {
+		return svgHeatmap(report, habit, palette)
+	}
+	tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(string(source))
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, report)
+}
+
+func periodName(report *types.Report) string {
+	switch report.Period {
+	case service.ReportPeriodWeek:
+		return "Week of " + report.Start.Format("Jan 2, 2006")
+	case service.ReportPeriodMonth:
+		return report.Start.Format("January 2006")
+	}
+	return report.Start.Format("2006")
+}
+
+const (
+	glyphDone      = "█"
+	glyphMissed    = "░"
+	glyphUntracked = "·"
+)
+
+func habitHeatmap(report *types.Report, habit types.HabitReport) heatmap.Heatmap {
+	return heatmap.Heatmap{
+		Title:       habit.Habit.Name,
+		Start:       report.Start,
+		Days:        habit.Heatmap,
+		TrackedFrom: habit.Habit.CreatedAt,
+		Today:       report.Today,
+		WeekStart:   report.WeekStart,
+	}
+}
+
+// textHeatmap draws short periods on one line in groups of a week,
+// longer ones as a grid with a row per weekday and a column per week.
+func textHeatmap(report *types.Report, habit types.HabitReport) string {
+	h := habitHeatmap(report, habit)
+	glyph := func(i int) string {
+		switch h.State(i) {
+		case heatmap.Done:
+			return glyphDone
+		case heatmap.Missed:
+			return glyphMissed
+		}
+		return glyphUntracked
+	}
+	if len(h.Days) <= 31 {
+		var sb strings.Builder
+		for i := range h.Days {
+			if _, row := h.Cell(i); i > 0 && row == 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(glyph(i))
+		}
+		return sb.String()
+	}
+	grid := make([][]string, 7)
+	for row := range grid {
+		grid[row] = make([]string, h.Weeks())
+		for column := range grid[row] {
+			grid[row][column] = " "
+		}
+	}
+	for i := range h.Days {
+		column, row := h.Cell(i)
+		grid[row][column] = glyph(i)
+	}
+	rows := make([]string, 0, 7)
+	for row, cells := range grid {
+		label := h.Weekday(row).String()[:3]
+		rows = append(rows, strings.TrimRight(label+" "+strings.Join(cells, ""), " "))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// svgHeatmap draws the bare grid, the report template labels it.
+func svgHeatmap(report *types.Report, habit types.HabitReport, palette heatmap.Palette) (string, error) {
+	var sb strings.Builder
+	err := heatmap.WriteSVG(&sb, habitHeatmap(report, habit), palette, false)
+	return strings.TrimSuffix(sb.String(), "\n"), err
+}
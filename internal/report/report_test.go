@@ -0,0 +1,92 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/heatmap"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPalette = heatmap.Palette{
+	Done:      "#25a425",
+	Missed:    "#cccccc",
+	Untracked: "#f0f0f0",
+	Text:      "#666666",
+}
+
+func testReport() *types.Report {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local)
+	habit := types.HabitReport{
+		HabitInfo:             types.HabitInfo{Habit: generated.Habit{Name: "running <5k>", CreatedAt: start.AddDate(0, 0, -30)}},
+		Done:                  2,
+		Tracked:               3,
+		Completion:            200.0 / 3,
+		PreviousCompletion:    50,
+		HasPrevious:           true,
+		LongestStreak:         2,
+		PreviousLongestStreak: 3,
+		Heatmap:               []bool{true, true, false, false, false, false, false},
+	}
+	return &types.Report{
+		Period:        "week",
+		Start:         start,
+		End:           start.AddDate(0, 0, 6),
+		PreviousStart: start.AddDate(0, 0, -7),
+		PreviousEnd:   start.AddDate(0, 0, -1),
+		Today:         start.AddDate(0, 0, 2),
+		WeekStart:     time.Monday,
+		Habits:        []types.HabitReport{habit},
+	}
+}
+
+func TestRender_Markdown(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, Render(&sb, testReport(), FormatMarkdown, t.TempDir(), testPalette))
+	out := sb.String()
+	assert.Contains(t, out, "# Habit report: Week of Mar 2, 2026")
+	assert.Contains(t, out, "| running <5k> | 2/3 | 67% | +17% | 2 | -1 | 0 |")
+	// done, done, missed today, then days to come
+	assert.Contains(t, out, "██░····")
+}
+
+func TestRender_HTML(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, Render(&sb, testReport(), FormatHTML, "", testPalette))
+	out := sb.String()
+	assert.Contains(t, out, "<td>running &lt;5k&gt;</td>")
+	assert.Contains(t, out, `<svg xmlns="http://www.w3.org/2000/svg" width="12" height="84" viewBox="0 0 12 84">`)
+	assert.Equal(t, 7, strings.Count(out, "<rect "))
+}
+
+func TestRender_CustomTemplate(t *testing.T) {
+	dir := t.TempDir()
+	custom := "{{ range .Habits }}{{ .Habit.Name }}: {{ pct .Completion }}{{ end }}"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, TemplateName(FormatMarkdown)), []byte(custom), 0600))
+
+	var sb strings.Builder
+	require.NoError(t, Render(&sb, testReport(), FormatMarkdown, dir, testPalette))
+	assert.Equal(t, "running <5k>: 67%", sb.String())
+
+	assert.Error(t, Render(&sb, testReport(), "pdf", dir, testPalette))
+}
+
+func TestTextHeatmap_WeekStart(t *testing.T) {
+	report := testReport()
+	report.WeekStart = time.Sunday
+	// the week of monday the 2nd is split before sunday the 8th
+	assert.Equal(t, "██░··· ·", textHeatmap(report, report.Habits[0]))
+
+	report.Start = report.Start.AddDate(0, 0, -42)
+	report.Habits[0].Heatmap = make([]bool, 49)
+	rows := strings.Split(textHeatmap(report, report.Habits[0]), "\n")
+	require.Len(t, rows, 7)
+	assert.True(t, strings.HasPrefix(rows[0], "Sun "))
+	assert.True(t, strings.HasPrefix(rows[6], "Sat "))
+}
@@ -0,0 +1,322 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/ics"
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+)
+
+const dateLayout = time.DateOnly
+
+type habitResponse struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	HabitType   string    `json:"habit_type"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type habitInfoResponse struct {
+	Habit              habitResponse `json:"habit"`
+	CurrentStreak      int64         `json:"current_streak"`
+	MaxStreak          int64         `json:"max_streak"`
+	TotalPerformedDays int64         `json:"total_performed_days"`
+	TotalMissedDays    int64         `json:"total_missed_days"`
+	Strength           float64       `json:"strength"`
+}
+
+type overallStatsResponse struct {
+	HabitInfos []habitInfoResponse `json:"habit_infos"`
+}
+
+type habitStatsForRangeResponse struct {
+	Habit                  habitResponse `json:"habit"`
+	Heatmap                []bool        `json:"heatmap"`
+	TotalStreakDaysInRange int           `json:"total_streak_days_in_range"`
+	TotalMissesInRange     int           `json:"total_misses_in_range"`
+	RangeStart             string        `json:"range_start"`
+	RangeEnd               string        `json:"range_end"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func toHabitResponse(habit generated.Habit) habitResponse {
+	return habitResponse{
+		ID:          habit.ID,
+		Name:        habit.Name,
+		Slug:        habit.Slug,
+		Description: habit.Description.String,
+		HabitType:   habit.HabitType,
+		CreatedAt:   habit.CreatedAt,
+	}
+}
+
+func toOverallStatsResponse(overallStats *types.OverallStats) overallStatsResponse {
+	res := overallStatsResponse{HabitInfos: make([]habitInfoResponse, 0, len(overallStats.HabitInfos))}
+	for _, habitInfo := range overallStats.HabitInfos {
+		res.HabitInfos = append(res.HabitInfos, habitInfoResponse{
+			Habit:              toHabitResponse(habitInfo.Habit),
+			CurrentStreak:      habitInfo.CurrentStreak,
+			MaxStreak:          habitInfo.MaxStreak,
+			TotalPerformedDays: habitInfo.TotalPerformedDays,
+			TotalMissedDays:    habitInfo.TotalMissedDays,
+			Strength:           habitInfo.Strength,
+		})
+	}
+	return res
+}
+
+func toHabitStatsForRangeResponse(stats *types.HabitStatsForRange) habitStatsForRangeResponse {
+	return habitStatsForRangeResponse{
+		Habit:                  toHabitResponse(stats.Habit),
+		Heatmap:                stats.Heatmap,
+		TotalStreakDaysInRange: stats.TotalStreakDaysInRange,
+		TotalMissesInRange:     stats.TotalMissesInRange,
+		RangeStart:             stats.RangeStart.Format(dateLayout),
+		RangeEnd:               stats.RangeEnd.Format(dateLayout),
+	}
+}
+
+// Server exposes the service layer as a small REST api guarded by a bearer token.
+type Server struct {
+	token string
+	// feedToken only reads /calendar.ics, it is passed in the url by calendar apps so it must not grant more.
+	feedToken string
+	// writeMu serializes writes, sqlite allows a single writer at a time anyway.
+	writeMu sync.Mutex
+	mux     *http.ServeMux
+}
+
+func New(token, feedToken string) *Server {
+	s := &Server{
+		token:     token,
+		feedToken: feedToken,
+		mux:       http.NewServeMux(),
+	}
+	s.mux.HandleFunc("GET /habits", s.handleListHabits)
+	s.mux.HandleFunc("POST /habits/{name}/log", s.handleLog)
+	s.mux.HandleFunc("DELETE /habits/{name}/log", s.handleUnlog)
+	s.mux.HandleFunc("GET /habits/{name}/stats", s.handleHabitStats)
+	s.mux.HandleFunc("GET /stats", s.handleOverallStats)
+	s.mux.HandleFunc("GET /calendar.ics", s.handleCalendar)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	expected := s.token
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok && r.Method == http.MethodGet && r.URL.Path == "/calendar.ics" {
+		// calendar apps subscribe to a plain url and can't send headers
+		expected = s.feedToken
+		token = r.URL.Query().Get("token")
+		ok = token != ""
+	}
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "unauthorized"})
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("failed to write http response", "err", err.Error())
+	}
+}
+
+// writeError maps the kind of StreakrErrors (user facing) to a status, anything else is logged and reported as 500.
+func writeError(w http.ResponseWriter, err error) {
+	var streakrErr *se.StreakrError
+	kind := store.ClassifyError(err)
+	if kind == se.KindLocked {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "database is locked, try again"})
+		return
+	}
+	if errors.As(err, &streakrErr) && streakrErr.TerminalMsg != "" {
+		status := http.StatusBadRequest
+		switch kind {
+		case se.KindNotFound:
+			status = http.StatusNotFound
+		case se.KindAlreadyExists:
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, errorResponse{Error: streakrErr.TerminalMsg})
+		return
+	}
+	slog.Error("error in http handler", "err", err.Error())
+	writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "internal error"})
+}
+
+// parseDateParam reads a YYYY-MM-DD query param, falling back to def when absent.
+func parseDateParam(r *http.Request, key string, def time.Time) (time.Time, error) {
+	val := r.URL.Query().Get(key)
+	if val == "" {
+		return def, nil
+	}
+	date, err := time.ParseInLocation(dateLayout, val, time.Local)
+	if err != nil {
+		return date, &se.StreakrError{TerminalMsg: "invalid " + key + ": must be YYYY-MM-DD"}
+	}
+	return date, nil
+}
+
+func (s *Server) handleListHabits(w http.ResponseWriter, r *http.Request) {
+	habits, err := service.ListHabits(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	res := make([]habitResponse, 0, len(habits))
+	for _, habit := range habits {
+		res = append(res, toHabitResponse(habit))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) setLogged(w http.ResponseWriter, r *http.Request, logged bool) {
+	date, err := parseDateParam(r, "date", time.Now())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if logged {
+		err = service.LogHabitForDate(r.Context(), r.PathValue("name"), date)
+	} else {
+		err = service.UnlogHabitForDate(r.Context(), r.PathValue("name"), date)
+	}
+	if err == nil {
+		// an encrypted db lives in memory, save it now instead of on shutdown
+		err = store.Flush()
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleLog(w http.ResponseWriter, r *http.Request) {
+	s.setLogged(w, r, true)
+}
+
+func (s *Server) handleUnlog(w http.ResponseWriter, r *http.Request) {
+	s.setLogged(w, r, false)
+}
+
+func (s *Server) handleHabitStats(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	from, err := parseDateParam(r, "from", time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	to, err := parseDateParam(r, "to", from.AddDate(0, 1, -1))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if to.Before(from) {
+		writeError(w, &se.StreakrError{TerminalMsg: "to must not be before from"})
+		return
+	}
+	stats, err := service.GetHabitStatsForRange(r.Context(), r.PathValue("name"), from, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toHabitStatsForRangeResponse(stats))
+}
+
+func (s *Server) handleOverallStats(w http.ResponseWriter, r *http.Request) {
+	overallStats, err := service.GetOverallStats(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toOverallStatsResponse(overallStats))
+}
+
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	habitName := r.URL.Query().Get("habit")
+	events, err := service.GetCalendarEvents(r.Context(), habitName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	calendarName := "streakr"
+	if habitName != "" {
+		calendarName += ": " + habitName
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if err = ics.Write(w, calendarName, events, time.Now()); err != nil {
+		slog.Error("failed to write calendar", "err", err.Error())
+	}
+}
+
+// LoadOrCreateToken reads the bearer token from tokenPath, generating a random one on first use.
+func LoadOrCreateToken(tokenPath string) (string, error) {
+	data, err := os.ReadFile(tokenPath)
+	if err == nil {
+		token := strings.TrimSpace(string(data))
+		if token != "" {
+			return token, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	if err = os.WriteFile(tokenPath, []byte(token+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ListenAndServe serves the api on addr until appContext is cancelled.
+func ListenAndServe(appContext context.Context, addr string, handler http.Handler) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return appContext },
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- httpServer.ListenAndServe()
+	}()
+	select {
+	case err := <-errChan:
+		return err
+	case <-appContext.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testToken     = "secret"
+	testFeedToken = "feed"
+)
+
+func setupTestServer(t *testing.T) (*httptest.Server, *service.TestDB) {
+	t.Helper()
+	testDB := service.SetupTestDB(t)
+	// an in-memory db only lives on one connection
+	testDB.DB.SetMaxOpenConns(1)
+	ts := httptest.NewServer(New(testToken, testFeedToken))
+	t.Cleanup(func() {
+		ts.Close()
+		testDB.Cleanup()
+	})
+	return ts, testDB
+}
+
+func doRequest(t *testing.T, ts *httptest.Server, method, path, token string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { res.Body.Close() })
+	return res
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	ts, _ := setupTestServer(t)
+
+	res := doRequest(t, ts, http.MethodGet, "/habits", "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	res = doRequest(t, ts, http.MethodGet, "/habits", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestServer_ListHabits(t *testing.T) {
+	ts, testDB := setupTestServer(t)
+	ctx := context.Background()
+	testDB.CreateTestHabit(t, ctx, "running", "5k", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "smoking", "", store.HabitTypeQuit, nil)
+
+	res := doRequest(t, ts, http.MethodGet, "/habits", testToken)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var habits []habitResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&habits))
+	require.Len(t, habits, 2)
+	assert.Equal(t, "running", habits[0].Name)
+	assert.Equal(t, "5k", habits[0].Description)
+	assert.Equal(t, store.HabitTypeQuit, habits[1].HabitType)
+}
+
+func TestServer_LogAndUnlog(t *testing.T) {
+	ts, testDB := setupTestServer(t)
+	ctx := context.Background()
+	createdAt := time.Now().AddDate(0, 0, -10)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	yesterday := time.Now().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	res := doRequest(t, ts, http.MethodPost, "/habits/running/log?date="+yesterday, testToken)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	res = doRequest(t, ts, http.MethodPost, "/habits/running/log", testToken)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	streaks, err := testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+
+	res = doRequest(t, ts, http.MethodDelete, "/habits/running/log", testToken)
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	streaks, err = testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+	assert.Equal(t, yesterday, streaks[0].StreakEnd.Format(time.DateOnly))
+
+	res = doRequest(t, ts, http.MethodPost, "/habits/nonexistent/log", testToken)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	res = doRequest(t, ts, http.MethodPost, "/habits/running/log?date=yesterday", testToken)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestServer_Stats(t *testing.T) {
+	ts, testDB := setupTestServer(t)
+	ctx := context.Background()
+	createdAt := time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestStreak(t, ctx, habit.ID, createdAt, createdAt.AddDate(0, 0, 4))
+
+	res := doRequest(t, ts, http.MethodGet, "/habits/running/stats?from=2025-11-01&to=2025-11-30", testToken)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var rangeStats habitStatsForRangeResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&rangeStats))
+	assert.Len(t, rangeStats.Heatmap, 30)
+	assert.Equal(t, 5, rangeStats.TotalStreakDaysInRange)
+	assert.Equal(t, "2025-11-01", rangeStats.RangeStart)
+
+	res = doRequest(t, ts, http.MethodGet, "/stats", testToken)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var overallStats overallStatsResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&overallStats))
+	require.Len(t, overallStats.HabitInfos, 1)
+	assert.Equal(t, int64(5), overallStats.HabitInfos[0].MaxStreak)
+}
+
+func TestServer_ConcurrentRequests(t *testing.T) {
+	ts, testDB := setupTestServer(t)
+	ctx := context.Background()
+	createdAt := time.Now().AddDate(0, 0, -30)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		date := time.Now().AddDate(0, 0, -i).Format(time.DateOnly)
+		go func() {
+			defer wg.Done()
+			res := doRequest(t, ts, http.MethodPost, "/habits/running/log?date="+date, testToken)
+			assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		}()
+		go func() {
+			defer wg.Done()
+			res := doRequest(t, ts, http.MethodGet, "/stats", testToken)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		}()
+	}
+	wg.Wait()
+
+	streaks, err := testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+	assert.Equal(t, 20, int(streaks[0].StreakEnd.Sub(streaks[0].StreakStart).Hours()/24)+1)
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "server_token")
+
+	token, err := LoadOrCreateToken(tokenPath)
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	again, err := LoadOrCreateToken(tokenPath)
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+}
+
+func TestServer_Calendar(t *testing.T) {
+	ts, testDB := setupTestServer(t)
+	ctx := context.Background()
+	createdAt := time.Now().AddDate(0, 0, -10)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestStreak(t, ctx, habit.ID, time.Now().AddDate(0, 0, -3), time.Now().AddDate(0, 0, -1))
+
+	// calendar apps pass the feed token in the url
+	res := doRequest(t, ts, http.MethodGet, "/calendar.ics?token="+testFeedToken, "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/calendar; charset=utf-8", res.Header.Get("Content-Type"))
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "SUMMARY:🔥 running\\, 3 day streak\r\n")
+
+	res = doRequest(t, ts, http.MethodGet, "/calendar.ics?token=wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	// the api token never goes in a url, and the feed token opens nothing else
+	res = doRequest(t, ts, http.MethodGet, "/calendar.ics?token="+testToken, "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	res = doRequest(t, ts, http.MethodGet, "/habits", testFeedToken)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	res = doRequest(t, ts, http.MethodGet, "/habits?token="+testFeedToken, "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	res = doRequest(t, ts, http.MethodGet, "/calendar.ics?habit=swimming", testToken)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{&se.StreakrError{TerminalMsg: "No habit with name x", Kind: se.KindNotFound}, http.StatusNotFound},
+		{&se.StreakrError{TerminalMsg: "already exists", Kind: se.KindAlreadyExists}, http.StatusConflict},
+		{&se.StreakrError{TerminalMsg: "invalid date"}, http.StatusBadRequest},
+		{fmt.Errorf("log: %w", sqlite3.Error{Code: sqlite3.ErrBusy}), http.StatusServiceUnavailable},
+		{errors.New("disk on fire"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		writeError(rec, tt.err)
+		assert.Equal(t, tt.status, rec.Code, tt.err.Error())
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+const (
+	perfectWeekDays   = 7
+	comebackBreakDays = 7
+	comebackStreak    = 3
+)
+
+// achievementState is everything the rules need, loaded once per evaluation.
+type achievementState struct {
+	habitInfos []types.HabitInfo
+	streaks    map[int64][]generated.Streak
+	today      time.Time
+}
+
+// achievementRule computes progress towards an achievement, per habit rules are evaluated for every
+// habit they apply to, global rules (perHabit false) once with a nil habit.
+type achievementRule struct {
+	code        string
+	title       string
+	description string
+	perHabit    bool
+	appliesTo   func(habit generated.Habit) bool
+	progress    func(state achievementState, habitInfo *types.HabitInfo) (current, target int64)
+}
+
+func isImproveHabit(habit generated.Habit) bool { return habit.HabitType == store.HabitTypeImprove }
+func isQuitHabit(habit generated.Habit) bool    { return habit.HabitType == store.HabitTypeQuit }
+
+func streakRule(days int64) achievementRule {
+	return achievementRule{
+		code:        fmt.Sprintf("streak_%d", days),
+		title:       fmt.Sprintf("%d-day streak", days),
+		description: fmt.Sprintf("Keep an improve habit going for %d days in a row", days),
+		perHabit:    true,
+		appliesTo:   isImproveHabit,
+		progress: func(_ achievementState, habitInfo *types.HabitInfo) (int64, int64) {
+			return habitInfo.MaxStreak, days
+		},
+	}
+}
+
+var achievementRules = []achievementRule{
+	streakRule(7),
+	streakRule(30),
+	streakRule(100),
+	streakRule(365),
+	{
+		code:        "clean_30",
+		title:       "30 clean days",
+		description: "Stay away from a quit habit for 30 days in a row",
+		perHabit:    true,
+		appliesTo:   isQuitHabit,
+		progress: func(_ achievementState, habitInfo *types.HabitInfo) (int64, int64) {
+			return habitInfo.MaxStreak, 30
+		},
+	},
+	{
+		code:        "comeback",
+		title:       "Comeback",
+		description: fmt.Sprintf("Build a %d-day streak after a break of %d days or more", comebackStreak, comebackBreakDays),
+		perHabit:    true,
+		appliesTo:   isImproveHabit,
+		progress: func(state achievementState, habitInfo *types.HabitInfo) (int64, int64) {
+			return getComebackProgress(state.streaks[habitInfo.Habit.ID]), comebackStreak
+		},
+	},
+	{
+		code:        "perfect_week",
+		title:       "Perfect week",
+		description: fmt.Sprintf("Log every improve habit for %d days in a row", perfectWeekDays),
+		perHabit:    false,
+		progress: func(state achievementState, _ *types.HabitInfo) (int64, int64) {
+			return getPerfectDaysInARow(state), perfectWeekDays
+		},
+	},
+}
+
+// getComebackProgress returns the longest streak that started after a break of comebackBreakDays or more.
+func getComebackProgress(streaks []generated.Streak) int64 {
+	var best int64
+	for i := 1; i < len(streaks); i++ {
+		missedDays := util.GetDayDiff(streaks[i-1].StreakEnd, streaks[i].StreakStart) - 1
+		if missedDays < comebackBreakDays {
+			continue
+		}
+		streakLen := int64(util.GetDayDiff(streaks[i].StreakStart, streaks[i].StreakEnd) + 1)
+		if streakLen > best {
+			best = streakLen
+		}
+	}
+	return best
+}
+
+// getPerfectDaysInARow counts the days up to today (or yesterday if today isn't perfect yet) on which
+// every improve habit existing that day was logged.
+func getPerfectDaysInARow(state achievementState) int64 {
+	improveHabits := make([]types.HabitInfo, 0)
+	for _, habitInfo := range state.habitInfos {
+		if isImproveHabit(habitInfo.Habit) {
+			improveHabits = append(improveHabits, habitInfo)
+		}
+	}
+	if len(improveHabits) == 0 {
+		return 0
+	}
+	loggedDays := make(map[int64]map[string]bool)
+	for _, habitInfo := range improveHabits {
+		loggedDays[habitInfo.Habit.ID] = make(map[string]bool)
+		for _, day := range store.LoggedDays(habitInfo.Habit, state.streaks[habitInfo.Habit.ID]) {
+			loggedDays[habitInfo.Habit.ID][day.Format(time.DateOnly)] = true
+		}
+	}
+	isPerfect := func(date time.Time) bool {
+		anyHabit := false
+		for _, habitInfo := range improveHabits {
+			if util.CompareDate(habitInfo.Habit.CreatedAt, date) == -1 {
+				continue
+			}
+			anyHabit = true
+			if !loggedDays[habitInfo.Habit.ID][date.Format(time.DateOnly)] {
+				return false
+			}
+		}
+		return anyHabit
+	}
+
+	date := state.today
+	if !isPerfect(date) {
+		date = util.GetPrevDayOf(date)
+	}
+	var days int64
+	for ; isPerfect(date); date = util.GetPrevDayOf(date) {
+		days++
+	}
+	return days
+}
+
+func loadAchievementState(appContext context.Context) (achievementState, error) {
+	overallStats, err := GetOverallStats(appContext)
+	if err != nil {
+		return achievementState{}, err
+	}
+	state := achievementState{
+		habitInfos: overallStats.HabitInfos,
+		streaks:    make(map[int64][]generated.Streak),
+		today:      time.Now(),
+	}
+	for _, habitInfo := range overallStats.HabitInfos {
+		streaks, err := store.GetQueries().ListStreaksForHabit(appContext, habitInfo.Habit.ID)
+		if err != nil {
+			return state, err
+		}
+		state.streaks[habitInfo.Habit.ID] = streaks
+	}
+	return state, nil
+}
+
+// getAchievementProgress evaluates every rule against the current state, without persisting anything.
+func getAchievementProgress(state achievementState) []types.AchievementProgress {
+	progress := make([]types.AchievementProgress, 0)
+	for _, rule := range achievementRules {
+		if !rule.perHabit {
+			current, target := rule.progress(state, nil)
+			progress = append(progress, types.AchievementProgress{
+				Code:        rule.code,
+				Title:       rule.title,
+				Description: rule.description,
+				Current:     min(current, target),
+				Target:      target,
+			})
+			continue
+		}
+		for i := range state.habitInfos {
+			habitInfo := &state.habitInfos[i]
+			if !rule.appliesTo(habitInfo.Habit) {
+				continue
+			}
+			current, target := rule.progress(state, habitInfo)
+			progress = append(progress, types.AchievementProgress{
+				Code:        rule.code,
+				Title:       rule.title,
+				Description: rule.description,
+				HabitName:   habitInfo.Habit.Name,
+				Current:     min(current, target),
+				Target:      target,
+			})
+		}
+	}
+	return progress
+}
+
+func achievementKey(code string, habitID int64) string {
+	return fmt.Sprintf("%s:%d", code, habitID)
+}
+
+// EvaluateAchievements persists every achievement whose target is reached and returns the newly unlocked ones.
+func EvaluateAchievements(appContext context.Context) ([]types.AchievementProgress, error) {
+	state, err := loadAchievementState(appContext)
+	if err != nil {
+		return nil, err
+	}
+	habitIDs := make(map[string]int64)
+	for _, habitInfo := range state.habitInfos {
+		habitIDs[habitInfo.Habit.Name] = habitInfo.Habit.ID
+	}
+	unlocked := make([]types.AchievementProgress, 0)
+	for _, progress := range getAchievementProgress(state) {
+		if progress.Current < progress.Target {
+			continue
+		}
+		habitID, perHabit := habitIDs[progress.HabitName]
+		rowsAffected, err := store.GetQueries().UnlockAchievement(appContext, generated.UnlockAchievementParams{
+			Code:    progress.Code,
+			HabitID: sql.NullInt64{Int64: habitID, Valid: perHabit},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if rowsAffected > 0 {
+			progress.Unlocked = true
+			progress.UnlockedAt = time.Now()
+			unlocked = append(unlocked, progress)
+		}
+	}
+	return unlocked, nil
+}
+
+// ListAchievements returns earned and pending achievements with their progress.
+// Earned achievements stay earned even if the habit's streak is later lost.
+func ListAchievements(appContext context.Context) ([]types.AchievementProgress, error) {
+	state, err := loadAchievementState(appContext)
+	if err != nil {
+		return nil, err
+	}
+	earned, err := store.GetQueries().ListAchievements(appContext)
+	if err != nil {
+		return nil, err
+	}
+	unlockedAt := make(map[string]time.Time)
+	for _, achievement := range earned {
+		unlockedAt[achievementKey(achievement.Code, achievement.HabitID.Int64)] = achievement.UnlockedAt
+	}
+	habitIDs := make(map[string]int64)
+	for _, habitInfo := range state.habitInfos {
+		habitIDs[habitInfo.Habit.Name] = habitInfo.Habit.ID
+	}
+	progress := getAchievementProgress(state)
+	for i := range progress {
+		at, ok := unlockedAt[achievementKey(progress[i].Code, habitIDs[progress[i].HabitName])]
+		if ok {
+			progress[i].Unlocked = true
+			progress[i].UnlockedAt = at
+			progress[i].Current = progress[i].Target
+		}
+	}
+	return progress, nil
+}
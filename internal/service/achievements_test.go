@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findAchievement(achievements []types.AchievementProgress, code, habitName string) *types.AchievementProgress {
+	for i := range achievements {
+		if achievements[i].Code == code && achievements[i].HabitName == habitName {
+			return &achievements[i]
+		}
+	}
+	return nil
+}
+
+func TestEvaluateAchievements(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -40)
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &createdAt)
+	testDB.CreateTestStreak(t, ctx, running.ID, today.AddDate(0, 0, -7), today.AddDate(0, 0, -1))
+
+	unlocked, err := EvaluateAchievements(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, findAchievement(unlocked, "streak_7", "running"))
+	assert.NotNil(t, findAchievement(unlocked, "clean_30", "smoking"))
+	assert.NotNil(t, findAchievement(unlocked, "perfect_week", ""))
+	assert.Nil(t, findAchievement(unlocked, "streak_30", "running"))
+
+	// already unlocked achievements are not reported again
+	unlocked, err = EvaluateAchievements(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, unlocked)
+
+	achievements, err := ListAchievements(ctx)
+	require.NoError(t, err)
+	streak7 := findAchievement(achievements, "streak_7", "running")
+	require.NotNil(t, streak7)
+	assert.True(t, streak7.Unlocked)
+	streak30 := findAchievement(achievements, "streak_30", "running")
+	require.NotNil(t, streak30)
+	assert.False(t, streak30.Unlocked)
+	assert.Equal(t, int64(7), streak30.Current)
+	assert.Equal(t, int64(30), streak30.Target)
+	// streak rules only apply to improve habits
+	assert.Nil(t, findAchievement(achievements, "streak_7", "smoking"))
+}
+
+func TestGetComebackProgress(t *testing.T) {
+	day := func(offset int) time.Time {
+		return time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local).AddDate(0, 0, offset)
+	}
+	tests := []struct {
+		name    string
+		streaks []generated.Streak
+		want    int64
+	}{
+		{name: "no streaks", streaks: nil, want: 0},
+		{
+			name: "short break",
+			streaks: []generated.Streak{
+				{StreakStart: day(0), StreakEnd: day(2)},
+				{StreakStart: day(5), StreakEnd: day(9)},
+			},
+			want: 0,
+		},
+		{
+			name: "comeback after long break",
+			streaks: []generated.Streak{
+				{StreakStart: day(0), StreakEnd: day(2)},
+				{StreakStart: day(10), StreakEnd: day(13)},
+			},
+			want: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getComebackProgress(tt.streaks))
+		})
+	}
+}
+
+func TestGetPerfectDaysInARow(t *testing.T) {
+	today := time.Date(2025, 11, 20, 12, 0, 0, 0, time.Local)
+	running := generated.Habit{ID: 1, HabitType: store.HabitTypeImprove, CreatedAt: today.AddDate(0, 0, -30)}
+	reading := generated.Habit{ID: 2, HabitType: store.HabitTypeImprove, CreatedAt: today.AddDate(0, 0, -3)}
+	state := achievementState{
+		habitInfos: []types.HabitInfo{{Habit: running}, {Habit: reading}},
+		streaks: map[int64][]generated.Streak{
+			1: {{StreakStart: today.AddDate(0, 0, -10), StreakEnd: today.AddDate(0, 0, -1)}},
+			2: {{StreakStart: today.AddDate(0, 0, -3), StreakEnd: today.AddDate(0, 0, -2)}},
+		},
+		today: today,
+	}
+	// yesterday reading was missed, so nothing counts
+	assert.Equal(t, int64(0), getPerfectDaysInARow(state))
+
+	// reading only counts from its creation day
+	state.streaks[2] = []generated.Streak{{StreakStart: today.AddDate(0, 0, -3), StreakEnd: today}}
+	state.streaks[1] = []generated.Streak{{StreakStart: today.AddDate(0, 0, -10), StreakEnd: today}}
+	assert.Equal(t, int64(11), getPerfectDaysInARow(state))
+}
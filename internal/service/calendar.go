@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+// GetCalendarEvents turns the streaks of a habit, or of every habit if habitName is empty, into calendar events.
+func GetCalendarEvents(appContext context.Context, habitName string) ([]types.CalendarEvent, error) {
+	habits := make([]generated.Habit, 0)
+	if habitName != "" {
+		habit, err := GetHabitByName(appContext, habitName)
+		if err != nil {
+			return nil, err
+		}
+		habits = append(habits, habit)
+	} else {
+		var err error
+		if habits, err = store.GetQueries().ListHabits(appContext); err != nil {
+			return nil, err
+		}
+	}
+
+	events := make([]types.CalendarEvent, 0)
+	for _, habit := range habits {
+		streaks, err := store.GetQueries().ListStreaksForHabit(appContext, habit.ID)
+		if err != nil {
+			return nil, err
+		}
+		if isQuitHabit(habit) {
+			slipups, err := store.GetQueries().ListSlipupsForHabit(appContext, habit.ID)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, getSlipupEvents(habit, streaks, slipups)...)
+			continue
+		}
+		events = append(events, getStreakEvents(habit, streaks)...)
+	}
+	return events, nil
+}
+
+// getStreakEvents makes an event per streak, the uid is keyed by the habit and the day the streak
+// starts so the event of a growing, merged or split streak is updated in place. Row ids would do
+// neither, the text backend numbers streaks anew on every load.
+func getStreakEvents(habit generated.Habit, streaks []generated.Streak) []types.CalendarEvent {
+	events := make([]types.CalendarEvent, 0, len(streaks))
+	for _, streak := range streaks {
+		days := util.GetDayDiff(streak.StreakStart, streak.StreakEnd) + 1
+		summary := fmt.Sprintf("✅ %s", habit.Name)
+		if days > 1 {
+			summary = fmt.Sprintf("🔥 %s, %d day streak", habit.Name, days)
+		}
+		events = append(events, types.CalendarEvent{
+			UID:     fmt.Sprintf("%s-streak-%s@streakr", habit.Slug, streak.StreakStart.Format("20060102")),
+			Summary: summary,
+			Start:   streak.StreakStart,
+			Days:    days,
+		})
+	}
+	return events
+}
+
+// getSlipupEvents makes a one day event per slip-up of a quit habit.
+func getSlipupEvents(habit generated.Habit, streaks []generated.Streak, slipups []generated.Slipup) []types.CalendarEvent {
+	countsByDay := make(map[string]int64)
+	for _, slipup := range slipups {
+		countsByDay[slipup.SlipDate.Format(time.DateOnly)] = slipup.Count
+	}
+	events := make([]types.CalendarEvent, 0, len(streaks))
+	for _, day := range store.LoggedDays(habit, streaks) {
+		summary := fmt.Sprintf("❌ %s slip-up", habit.Name)
+		if count := countsByDay[day.Format(time.DateOnly)]; count > 1 {
+			summary = fmt.Sprintf("❌ %s, %d slip-ups", habit.Name, count)
+		}
+		events = append(events, types.CalendarEvent{
+			UID:     fmt.Sprintf("%s-slipup-%s@streakr", habit.Slug, day.Format("20060102")),
+			Summary: summary,
+			Start:   day,
+			Days:    1,
+		})
+	}
+	return events
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCalendarEvents(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+	ctx := context.Background()
+	createdAt := time.Now().AddDate(0, 0, -20)
+	running := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	smoking := testDB.CreateTestHabit(t, ctx, "smoking", "", store.HabitTypeQuit, &createdAt)
+	start := time.Now().AddDate(0, 0, -5)
+	testDB.CreateTestStreak(t, ctx, running.ID, start, time.Now().AddDate(0, 0, -3))
+	testDB.CreateTestStreak(t, ctx, running.ID, time.Now(), time.Now())
+	slipDay := time.Now().AddDate(0, 0, -2)
+	testDB.CreateTestStreak(t, ctx, smoking.ID, createdAt, slipDay)
+	require.NoError(t, store.GetQueries().AddSlipupCount(ctx, generated.AddSlipupCountParams{HabitID: smoking.ID, SlipDate: slipDay, Count: 2}))
+
+	events, err := GetCalendarEvents(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	firstUID := events[0].UID
+	assert.Equal(t, "running-streak-"+start.Format("20060102")+"@streakr", firstUID)
+	assert.Equal(t, 3, events[0].Days)
+	assert.Equal(t, "🔥 running, 3 day streak", events[0].Summary)
+	assert.Equal(t, "✅ running", events[1].Summary)
+	assert.Equal(t, "smoking-slipup-"+slipDay.Format("20060102")+"@streakr", events[2].UID)
+	assert.Equal(t, "❌ smoking, 2 slip-ups", events[2].Summary)
+
+	events, err = GetCalendarEvents(ctx, "running")
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	// merging and splitting streaks keeps the event of the first one
+	require.NoError(t, LogHabitForDate(ctx, "running", time.Now().AddDate(0, 0, -2)))
+	require.NoError(t, LogHabitForDate(ctx, "running", time.Now().AddDate(0, 0, -1)))
+	events, err = GetCalendarEvents(ctx, "running")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, firstUID, events[0].UID)
+	assert.Equal(t, 6, events[0].Days)
+	require.NoError(t, UnlogHabitForDate(ctx, "running", time.Now().AddDate(0, 0, -3)))
+	events, err = GetCalendarEvents(ctx, "running")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, firstUID, events[0].UID)
+	assert.Equal(t, 2, events[0].Days)
+	assert.NotEqual(t, firstUID, events[1].UID)
+
+	_, err = GetCalendarEvents(ctx, "swimming")
+	assert.Error(t, err)
+}
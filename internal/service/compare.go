@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+// CompareHabits aligns the heatmaps of two habits from startDate to endDate and counts how often they were done together.
+// Only days both habits were tracked on are compared, today is left out as it isn't over yet.
+func CompareHabits(appContext context.Context, habitNameA, habitNameB string, startDate, endDate time.Time) (*types.HabitComparison, error) {
+	a, err := GetHabitStatsForRange(appContext, habitNameA, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	b, err := GetHabitStatsForRange(appContext, habitNameB, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if a.Habit.ID == b.Habit.ID {
+		return nil, &se.StreakrError{TerminalMsg: "cannot compare a habit with itself"}
+	}
+
+	comparison := &types.HabitComparison{A: a, B: b, Compared: make([]bool, len(a.Heatmap))}
+	from := trackedFrom(a.Habit)
+	if fromB := trackedFrom(b.Habit); util.CompareDate(from, fromB) == 1 {
+		from = fromB
+	}
+	yesterday := util.GetPrevDayOf(time.Now())
+	for i := range comparison.Compared {
+		date := util.GetDateWithDaysDiff(startDate, i)
+		if util.CompareDate(date, from) == 1 || util.CompareDate(date, yesterday) == -1 {
+			continue
+		}
+		comparison.Compared[i] = true
+		switch {
+		case a.Heatmap[i] && b.Heatmap[i]:
+			comparison.BothDone++
+		case a.Heatmap[i]:
+			comparison.OnlyADone++
+		case b.Heatmap[i]:
+			comparison.OnlyBDone++
+		default:
+			comparison.NeitherDone++
+		}
+	}
+	return comparison, nil
+}
+
+// trackedFrom is the first day a habit counts as done or missed, quit habits are clean from the day after creation.
+func trackedFrom(habit generated.Habit) time.Time {
+	if isQuitHabit(habit) {
+		return util.GetNextDayOf(habit.CreatedAt)
+	}
+	return habit.CreatedAt
+}
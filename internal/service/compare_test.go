@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareHabits(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 12, 0, 0, 0, time.Local) }
+
+	createdAt := day(1)
+	gym := testDB.CreateTestHabit(t, ctx, "gym", "test", store.HabitTypeImprove, &createdAt)
+	sleep := testDB.CreateTestHabit(t, ctx, "sleep", "test", store.HabitTypeImprove, &createdAt)
+	laterCreatedAt := day(6)
+	testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, &laterCreatedAt)
+	testDB.CreateTestStreak(t, ctx, gym.ID, day(1), day(5))
+	testDB.CreateTestStreak(t, ctx, sleep.ID, day(1), day(3))
+	testDB.CreateTestStreak(t, ctx, sleep.ID, day(8), day(9))
+
+	comparison, err := CompareHabits(ctx, "gym", "sleep", day(1), day(10))
+	require.NoError(t, err)
+	assert.Equal(t, 3, comparison.BothDone)
+	assert.Equal(t, 2, comparison.OnlyADone)
+	assert.Equal(t, 2, comparison.OnlyBDone)
+	assert.Equal(t, 3, comparison.NeitherDone)
+	assert.Equal(t, 10, comparison.Days())
+	assert.InDelta(t, 60, comparison.BDoneWhenADone(), 0.01)
+	assert.InDelta(t, 40, comparison.BDoneWhenAMissed(), 0.01)
+	assert.InDelta(t, 60, comparison.Agreement(), 0.01)
+	phi, ok := comparison.Phi()
+	require.True(t, ok)
+	assert.InDelta(t, 0.2, phi, 0.001)
+
+	// days before either habit was created aren't compared
+	comparison, err = CompareHabits(ctx, "gym", "reading", day(1), day(10))
+	require.NoError(t, err)
+	assert.Equal(t, 5, comparison.Days())
+	assert.False(t, comparison.Compared[4])
+	assert.True(t, comparison.Compared[5])
+
+	_, err = CompareHabits(ctx, "gym", "gym", day(1), day(10))
+	assert.Error(t, err)
+	_, err = CompareHabits(ctx, "gym", "swimming", day(1), day(10))
+	assert.Error(t, err)
+}
+
+func TestHabitComparisonPhi(t *testing.T) {
+	phi, ok := types.HabitComparison{BothDone: 4, NeitherDone: 6}.Phi()
+	require.True(t, ok)
+	assert.InDelta(t, 1, phi, 0.001)
+	phi, ok = types.HabitComparison{OnlyADone: 4, OnlyBDone: 6}.Phi()
+	require.True(t, ok)
+	assert.InDelta(t, -1, phi, 0.001)
+	// a habit that was always done has no correlation to speak of
+	_, ok = types.HabitComparison{BothDone: 4, OnlyADone: 6}.Phi()
+	assert.False(t, ok)
+}
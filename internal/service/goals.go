@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+// AddGoal attaches a goal to a habit, start is the first day counted towards it.
+func AddGoal(appContext context.Context, habitName, kind string, target int64, start, deadline time.Time) (int64, error) {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return 0, err
+	}
+	if kind != store.GoalKindTotalDays && kind != store.GoalKindStreak && kind != store.GoalKindRate {
+		return 0, &se.StreakrError{
+			TerminalMsg: fmt.Sprintf(
+				"invalid goal kind %s, must be one of %s, %s or %s",
+				kind,
+				store.GoalKindTotalDays,
+				store.GoalKindStreak,
+				store.GoalKindRate,
+			),
+		}
+	}
+	if target <= 0 {
+		return 0, &se.StreakrError{TerminalMsg: "goal target must be greater than 0"}
+	}
+	if util.CompareDate(deadline, start) == 1 {
+		return 0, &se.StreakrError{TerminalMsg: "goal deadline cannot be before its start"}
+	}
+	if util.CompareDate(deadline, time.Now()) == 1 {
+		return 0, &se.StreakrError{TerminalMsg: "goal deadline cannot be in the past"}
+	}
+	windowDays := int64(util.GetDayDiff(start, deadline) + 1)
+	if kind == store.GoalKindRate && target > 100 {
+		return 0, &se.StreakrError{TerminalMsg: "completion rate target cannot exceed 100%"}
+	}
+	if kind != store.GoalKindRate && target > windowDays {
+		return 0, &se.StreakrError{
+			TerminalMsg: fmt.Sprintf("goal target of %d days does not fit in the %d days before the deadline", target, windowDays),
+		}
+	}
+	return store.GetQueries().AddGoal(appContext, generated.AddGoalParams{
+		HabitID:   habit.ID,
+		Kind:      kind,
+		Target:    target,
+		StartDate: util.GetNoonOf(start),
+		Deadline:  util.GetNoonOf(deadline),
+	})
+}
+
+func DeleteGoal(appContext context.Context, id int64) error {
+	deleted, err := store.GetQueries().DeleteGoal(appContext, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return &se.StreakrError{TerminalMsg: fmt.Sprintf("No goal with id %d", id), Kind: se.KindNotFound}
+	}
+	return nil
+}
+
+// ListGoalProgress computes the progress of every goal.
+func ListGoalProgress(appContext context.Context) ([]types.GoalProgress, error) {
+	goals, err := store.GetQueries().ListGoals(appContext)
+	if err != nil {
+		return nil, err
+	}
+	habits, err := ListHabits(appContext)
+	if err != nil {
+		return nil, err
+	}
+	habitsByID := make(map[int64]generated.Habit)
+	for _, habit := range habits {
+		habitsByID[habit.ID] = habit
+	}
+	streaksByHabitID := make(map[int64][]generated.Streak)
+	progress := make([]types.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		habit := habitsByID[goal.HabitID]
+		streaks, ok := streaksByHabitID[habit.ID]
+		if !ok {
+			streaks, err = store.GetQueries().ListStreaksForHabit(appContext, habit.ID)
+			if err != nil {
+				return nil, err
+			}
+			streaksByHabitID[habit.ID] = streaks
+		}
+		progress = append(progress, getGoalProgress(habit, goal, streaks, time.Now()))
+	}
+	return progress, nil
+}
+
+// GetGoalProgressForHabit computes the progress of the goals attached to habit.
+func GetGoalProgressForHabit(appContext context.Context, habit generated.Habit) ([]types.GoalProgress, error) {
+	goals, err := store.GetQueries().ListGoalsForHabit(appContext, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(goals) == 0 {
+		return []types.GoalProgress{}, nil
+	}
+	streaks, err := store.GetQueries().ListStreaksForHabit(appContext, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	progress := make([]types.GoalProgress, 0, len(goals))
+	for _, goal := range goals {
+		progress = append(progress, getGoalProgress(habit, goal, streaks, time.Now()))
+	}
+	return progress, nil
+}
+
+// getGoalProgress evaluates a goal against the daily completion of its habit.
+// rate goals are treated as a number of completed days over the whole goal window,
+// so all kinds share the same pace and projection math.
+func getGoalProgress(habit generated.Habit, goal generated.Goal, streaks []generated.Streak, today time.Time) types.GoalProgress {
+	progress := types.GoalProgress{
+		Goal:      goal,
+		HabitName: habit.Name,
+		Required:  goal.Target,
+	}
+	trackingStart := getTrackingStart(habit, streaks)
+	dailyCompletion := getDailyCompletion(habit, streaks, today)
+
+	var done, elapsed, run, bestRun int64
+	for i, completed := range dailyCompletion {
+		date := trackingStart.AddDate(0, 0, i)
+		if util.CompareDate(date, goal.StartDate) == 1 {
+			continue
+		}
+		if util.CompareDate(date, goal.Deadline) == -1 {
+			break
+		}
+		elapsed++
+		if !completed {
+			run = 0
+			continue
+		}
+		done++
+		run++
+		bestRun = max(bestRun, run)
+	}
+
+	// days still open for completion, today counts unless its outcome is already decided.
+	nextDay := trackingStart.AddDate(0, 0, len(dailyCompletion))
+	if util.CompareDate(nextDay, goal.StartDate) == 1 {
+		nextDay = goal.StartDate
+	}
+	var daysLeft int64
+	if util.CompareDate(nextDay, goal.Deadline) >= 0 {
+		daysLeft = int64(util.GetDayDiff(nextDay, goal.Deadline) + 1)
+	}
+
+	switch goal.Kind {
+	case store.GoalKindStreak:
+		progress.Current = bestRun
+	case store.GoalKindRate:
+		windowDays := float64(util.GetDayDiff(goal.StartDate, goal.Deadline) + 1)
+		progress.Required = int64(math.Ceil(float64(goal.Target) / 100 * windowDays))
+		progress.Current = done
+		if elapsed > 0 {
+			progress.Rate = float64(done) / float64(elapsed) * 100
+		}
+	default:
+		progress.Current = done
+	}
+
+	progress.Percent = min(float64(progress.Current)/float64(progress.Required)*100, 100)
+	if progress.Current >= progress.Required {
+		progress.Achieved = true
+		progress.OnTrack = true
+		return progress
+	}
+	if daysLeft == 0 {
+		progress.Expired = true
+		return progress
+	}
+
+	remaining := progress.Required - progress.Current
+	if goal.Kind == store.GoalKindStreak {
+		// a streak can only be completed by not breaking the running one.
+		remaining = progress.Required - run
+		progress.RequiredPerWeek = 7
+		progress.ProjectedDate = nextDay.AddDate(0, 0, int(remaining)-1)
+	} else {
+		progress.RequiredPerWeek = float64(remaining) / float64(daysLeft) * 7
+		if done > 0 {
+			daysPerCompletion := float64(elapsed) / float64(done)
+			progress.ProjectedDate = nextDay.AddDate(0, 0, int(math.Ceil(float64(remaining)*daysPerCompletion))-1)
+		}
+	}
+	progress.OnTrack = !progress.ProjectedDate.IsZero() && util.CompareDate(progress.ProjectedDate, goal.Deadline) >= 0
+	return progress
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGoalProgress(t *testing.T) {
+	createdAt := time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)
+	today := time.Date(2025, 11, 20, 12, 0, 0, 0, time.Local)
+	improve := generated.Habit{ID: 1, Name: "running", HabitType: store.HabitTypeImprove, CreatedAt: createdAt}
+	// 10 performed days: nov 1-5, nov 11-15, today not logged yet
+	streaks := []generated.Streak{
+		{HabitID: 1, StreakStart: createdAt, StreakEnd: time.Date(2025, 11, 5, 12, 0, 0, 0, time.Local)},
+		{HabitID: 1, StreakStart: time.Date(2025, 11, 11, 12, 0, 0, 0, time.Local), StreakEnd: time.Date(2025, 11, 15, 12, 0, 0, 0, time.Local)},
+	}
+
+	tests := []struct {
+		name          string
+		goal          generated.Goal
+		wantCurrent   int64
+		wantRequired  int64
+		wantAchieved  bool
+		wantExpired   bool
+		wantPerWeek   float64
+		wantProjected string
+		wantOnTrack   bool
+	}{
+		{
+			name: "total days",
+			goal: generated.Goal{
+				Kind:      store.GoalKindTotalDays,
+				Target:    20,
+				StartDate: createdAt,
+				Deadline:  time.Date(2025, 12, 10, 12, 0, 0, 0, time.Local),
+			},
+			wantCurrent:  10,
+			wantRequired: 20,
+			// 10 more days in the 21 days from today to dec 10
+			wantPerWeek: 10.0 / 21 * 7,
+			// 10 done in 19 days, so the next 10 take 19 days
+			wantProjected: "2025-12-08",
+			wantOnTrack:   true,
+		},
+		{
+			name: "streak",
+			goal: generated.Goal{
+				Kind:      store.GoalKindStreak,
+				Target:    7,
+				StartDate: createdAt,
+				Deadline:  time.Date(2025, 11, 25, 12, 0, 0, 0, time.Local),
+			},
+			wantCurrent:   5,
+			wantRequired:  7,
+			wantPerWeek:   7,
+			wantProjected: "2025-11-26",
+			wantOnTrack:   false,
+		},
+		{
+			name: "rate",
+			goal: generated.Goal{
+				Kind:      store.GoalKindRate,
+				Target:    50,
+				StartDate: createdAt,
+				Deadline:  time.Date(2025, 11, 30, 12, 0, 0, 0, time.Local),
+			},
+			wantCurrent:   10,
+			wantRequired:  15,
+			wantPerWeek:   5.0 / 11 * 7,
+			wantProjected: "2025-11-29",
+			wantOnTrack:   true,
+		},
+		{
+			name: "achieved",
+			goal: generated.Goal{
+				Kind:      store.GoalKindStreak,
+				Target:    5,
+				StartDate: createdAt,
+				Deadline:  time.Date(2025, 11, 25, 12, 0, 0, 0, time.Local),
+			},
+			wantCurrent:  5,
+			wantRequired: 5,
+			wantAchieved: true,
+			wantOnTrack:  true,
+		},
+		{
+			name: "expired",
+			goal: generated.Goal{
+				Kind:      store.GoalKindTotalDays,
+				Target:    12,
+				StartDate: createdAt,
+				Deadline:  time.Date(2025, 11, 15, 12, 0, 0, 0, time.Local),
+			},
+			wantCurrent:  10,
+			wantRequired: 12,
+			wantExpired:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := getGoalProgress(improve, tt.goal, streaks, today)
+			assert.Equal(t, tt.wantCurrent, progress.Current)
+			assert.Equal(t, tt.wantRequired, progress.Required)
+			assert.Equal(t, tt.wantAchieved, progress.Achieved)
+			assert.Equal(t, tt.wantExpired, progress.Expired)
+			assert.Equal(t, tt.wantOnTrack, progress.OnTrack)
+			assert.InDelta(t, tt.wantPerWeek, progress.RequiredPerWeek, 0.001)
+			if tt.wantProjected == "" {
+				assert.True(t, progress.ProjectedDate.IsZero())
+			} else {
+				assert.Equal(t, tt.wantProjected, progress.ProjectedDate.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestGetGoalProgress_QuitHabit(t *testing.T) {
+	createdAt := time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)
+	today := time.Date(2025, 11, 20, 12, 0, 0, 0, time.Local)
+	quit := generated.Habit{ID: 1, Name: "smoking", HabitType: store.HabitTypeQuit, CreatedAt: createdAt}
+	// slip-up on nov 10, clean nov 2-9 and nov 11-19
+	streaks := []generated.Streak{
+		{HabitID: 1, StreakStart: time.Date(2025, 11, 2, 12, 0, 0, 0, time.Local), StreakEnd: time.Date(2025, 11, 10, 12, 0, 0, 0, time.Local)},
+	}
+	goal := generated.Goal{
+		Kind:      store.GoalKindStreak,
+		Target:    30,
+		StartDate: createdAt,
+		Deadline:  time.Date(2025, 12, 31, 12, 0, 0, 0, time.Local),
+	}
+
+	progress := getGoalProgress(quit, goal, streaks, today)
+	assert.Equal(t, int64(9), progress.Current)
+	// the running clean streak of 9 days needs 21 more, counting today
+	assert.Equal(t, "2025-12-10", progress.ProjectedDate.Format(time.DateOnly))
+	assert.True(t, progress.OnTrack)
+}
+
+func TestAddAndDeleteGoal(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -10)
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestStreak(t, ctx, running.ID, today.AddDate(0, 0, -3), today.AddDate(0, 0, -1))
+
+	id, err := AddGoal(ctx, "running", store.GoalKindTotalDays, 10, createdAt, today.AddDate(0, 0, 30))
+	require.NoError(t, err)
+
+	_, err = AddGoal(ctx, "running", "weekly", 10, today, today.AddDate(0, 0, 30))
+	assert.Error(t, err, "invalid kind")
+	_, err = AddGoal(ctx, "running", store.GoalKindRate, 101, today, today.AddDate(0, 0, 30))
+	assert.Error(t, err, "rate above 100")
+	_, err = AddGoal(ctx, "running", store.GoalKindStreak, 40, today, today.AddDate(0, 0, 30))
+	assert.Error(t, err, "target longer than the window")
+	_, err = AddGoal(ctx, "running", store.GoalKindTotalDays, 1, today.AddDate(0, 0, -5), today.AddDate(0, 0, -1))
+	assert.Error(t, err, "deadline in the past")
+
+	goals, err := ListGoalProgress(ctx)
+	require.NoError(t, err)
+	require.Len(t, goals, 1)
+	assert.Equal(t, "running", goals[0].HabitName)
+	assert.Equal(t, int64(3), goals[0].Current)
+
+	habitGoals, err := GetGoalProgressForHabit(ctx, running)
+	require.NoError(t, err)
+	assert.Len(t, habitGoals, 1)
+
+	require.NoError(t, DeleteGoal(ctx, id))
+	assert.Error(t, DeleteGoal(ctx, id))
+}
@@ -5,34 +5,124 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/Atharva21/streakr/internal/events"
 	"github.com/Atharva21/streakr/internal/store"
 	"github.com/Atharva21/streakr/internal/store/generated"
 	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
 	"github.com/mattn/go-sqlite3"
+	"github.com/sahilm/fuzzy"
 )
 
+// GetHabitByName finds a habit by its slug, falling back to the exact display name.
 func GetHabitByName(appContext context.Context, name string) (generated.Habit, error) {
-	habit, err := store.GetQueries().GetHabitByName(appContext, name)
+	habit, err := store.GetQueries().GetHabitBySlug(appContext, util.Slugify(name))
+	if errors.Is(err, sql.ErrNoRows) {
+		habit, err = store.GetQueries().GetHabitByName(appContext, strings.TrimSpace(name))
+	}
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return habit, &se.StreakrError{TerminalMsg: fmt.Sprintf("No habit with name %s", name)}
+			msg := fmt.Sprintf("No habit with name %s", name)
+			if suggestion := suggestHabitName(appContext, name); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %s?", suggestion)
+			}
+			return habit, &se.StreakrError{TerminalMsg: msg, Kind: se.KindNotFound}
 		}
 		return habit, err
 	}
 	return habit, err
 }
 
+// maxSuggestionDistance is the largest edit distance at which a habit name is still suggested for a typo.
+const maxSuggestionDistance = 2
+
+// suggestHabitName returns the slug of the habit closest to a mistyped name, or "" if none is close.
+// fuzzy matching covers missing or extra letters, edit distance covers swapped and wrong ones.
+func suggestHabitName(appContext context.Context, name string) string {
+	name = util.Slugify(name)
+	if name == "" {
+		return ""
+	}
+	habits, err := store.GetQueries().ListHabits(appContext)
+	if err != nil || len(habits) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(habits))
+	for _, habit := range habits {
+		names = append(names, habit.Slug)
+	}
+	if matches := fuzzy.Find(name, names); len(matches) > 0 {
+		return matches[0].Str
+	}
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range names {
+		if len(fuzzy.Find(candidate, []string{name})) > 0 {
+			// the typo has extra letters
+			return candidate
+		}
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// AddHabit adds a habit, name is a display name that may contain spaces and emoji.
 func AddHabit(appContext context.Context, name, description, habitType string) error {
+	name = strings.TrimSpace(name)
 	// Validate name is not empty
 	if name == "" {
 		return &se.StreakrError{TerminalMsg: "Habit name cannot be empty"}
 	}
+	if util.DisplayWidth(name) > util.MaxHabitNameWidth {
+		return &se.StreakrError{TerminalMsg: "habit name cannot be wider than 20 characters, emoji count as 2"}
+	}
+	// the schema caps names at 20 code points as well, which joined emoji and combining marks can exceed
+	if utf8.RuneCountInString(name) > 20 {
+		return &se.StreakrError{TerminalMsg: "habit name has too many emoji or accents, shorten it"}
+	}
+	slug := util.Slugify(name)
+	if slug == "" {
+		return &se.StreakrError{TerminalMsg: "habit name must contain at least one letter or digit"}
+	}
 
-	_, err := store.GetQueries().AddHabit(
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	habitID, err := qtx.AddHabit(
 		appContext,
 		generated.AddHabitParams{
 			Name: name,
+			Slug: slug,
 			Description: sql.NullString{
 				String: description,
 				Valid:  description != "",
@@ -43,31 +133,59 @@ func AddHabit(appContext context.Context, name, description, habitType string) e
 	if err != nil {
 		if sqliteErr, ok := err.(sqlite3.Error); ok {
 			if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
-				return &se.StreakrError{TerminalMsg: fmt.Sprintf("Cannot add habit with name %s as it already exists", name)}
+				return &se.StreakrError{TerminalMsg: fmt.Sprintf("Cannot add habit with name %s as it already exists", name), Kind: se.KindAlreadyExists}
 			}
 		}
 		return err
 	}
+	habit, err := qtx.GetHabit(appContext, habitID)
+	if err != nil {
+		return err
+	}
+	if err = recordSyncOp(appContext, qtx, habitSyncOp(habit)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
 
+	events.Publish(appContext, events.Event{
+		Type: events.HabitAdded,
+		Habit: events.HabitPayload{
+			Name:        name,
+			Description: description,
+			HabitType:   habitType,
+		},
+	})
 	return nil
 }
 
 func DeleteHabits(appContext context.Context, queries []string) error {
-	habitsIDsToDelete := make([]int64, 0)
+	habitsToDelete := make([]generated.Habit, 0)
 	for _, query := range queries {
 		habit, err := GetHabitByName(appContext, query)
 		if err != nil {
 			return err
 		}
-		habitsIDsToDelete = append(habitsIDsToDelete, habit.ID)
+		habitsToDelete = append(habitsToDelete, habit)
 	}
-	for _, habitID := range habitsIDsToDelete {
-		err := store.GetQueries().DeleteHabit(appContext, habitID)
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	for _, habit := range habitsToDelete {
+		err := qtx.DeleteHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		err = recordSyncOp(appContext, qtx, types.SyncOp{Op: SyncOpDeleteHabit, Habit: habit.Slug})
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	return tx.Commit()
 }
 
 func ListHabits(appContext context.Context) ([]generated.Habit, error) {
@@ -85,3 +203,38 @@ func GetTodaysLoggedHabitCount(appContext context.Context) (int64, int64, error)
 	}
 	return completedImprovementHabits, totalImprovementHabits, nil
 }
+
+// GetStatusSummary summarizes today's progress, limited to habits carrying tag unless it is empty.
+func GetStatusSummary(appContext context.Context, tag string) (*types.StatusSummary, error) {
+	var loggedHabitCount, totalHabitCount int64
+	var overallStats *types.OverallStats
+	var err error
+	if tag == "" {
+		loggedHabitCount, totalHabitCount, err = GetTodaysLoggedHabitCount(appContext)
+		if err != nil {
+			return nil, err
+		}
+		overallStats, err = GetOverallStats(appContext)
+	} else {
+		loggedHabitCount, totalHabitCount, err = GetTodaysLoggedHabitCountByTag(appContext, tag)
+		if err != nil {
+			return nil, err
+		}
+		overallStats, err = GetOverallStatsByTag(appContext, tag)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var bestStreak int64
+	for _, habitInfo := range overallStats.HabitInfos {
+		if habitInfo.CurrentStreak > bestStreak {
+			bestStreak = habitInfo.CurrentStreak
+		}
+	}
+	return &types.StatusSummary{
+		Done:       loggedHabitCount,
+		Total:      totalHabitCount,
+		Remaining:  totalHabitCount - loggedHabitCount,
+		BestStreak: bestStreak,
+	}, nil
+}
@@ -114,6 +114,37 @@ func TestAddHabit_Duplicate(t *testing.T) {
 	assert.Contains(t, streakrErr.TerminalMsg, "already exists")
 }
 
+func TestAddHabit_DisplayName(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+
+	err := AddHabit(ctx, "  Morning Walk 🚶 ", "", store.HabitTypeImprove)
+	require.NoError(t, err)
+
+	for _, name := range []string{"Morning Walk 🚶", "morning-walk", "MORNING walk", "morning_walk"} {
+		habit, err := GetHabitByName(ctx, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "Morning Walk 🚶", habit.Name)
+		assert.Equal(t, "morning-walk", habit.Slug)
+	}
+
+	// a different display name with the same slug is a duplicate
+	err = AddHabit(ctx, "morning walk", "", store.HabitTypeImprove)
+	var streakrErr *se.StreakrError
+	require.ErrorAs(t, err, &streakrErr)
+	assert.Contains(t, streakrErr.TerminalMsg, "already exists")
+
+	// names without a letter or digit have no slug
+	err = AddHabit(ctx, "🏃", "", store.HabitTypeImprove)
+	require.ErrorAs(t, err, &streakrErr)
+
+	// the length limit counts characters, not bytes
+	err = AddHabit(ctx, "Çalışmak ve öğrenmek", "", store.HabitTypeImprove)
+	assert.NoError(t, err)
+}
+
 func TestGetHabitByName(t *testing.T) {
 	testDB := SetupTestDB(t)
 	defer testDB.Cleanup()
@@ -169,6 +200,39 @@ func TestGetHabitByName(t *testing.T) {
 	}
 }
 
+func TestGetHabitByName_Suggestion(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, AddHabit(ctx, "running", "", store.HabitTypeImprove))
+	require.NoError(t, AddHabit(ctx, "meditate", "", store.HabitTypeImprove))
+
+	tests := []struct {
+		habitName      string
+		wantSuggestion string
+	}{
+		{habitName: "runing", wantSuggestion: "running"},
+		{habitName: "runnning", wantSuggestion: "running"},
+		{habitName: "runnign", wantSuggestion: "running"},
+		{habitName: "medtiate", wantSuggestion: "meditate"},
+		{habitName: "swimming", wantSuggestion: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.habitName, func(t *testing.T) {
+			_, err := GetHabitByName(ctx, tt.habitName)
+			var streakrErr *se.StreakrError
+			require.ErrorAs(t, err, &streakrErr)
+			if tt.wantSuggestion == "" {
+				assert.NotContains(t, streakrErr.TerminalMsg, "did you mean")
+				return
+			}
+			assert.Contains(t, streakrErr.TerminalMsg, "did you mean "+tt.wantSuggestion+"?")
+		})
+	}
+}
+
 func TestListHabits(t *testing.T) {
 	testDB := SetupTestDB(t)
 	defer testDB.Cleanup()
@@ -352,3 +416,25 @@ func TestGetTodaysLoggedHabitCount(t *testing.T) {
 func timeDate(year, month, day int) time.Time {
 	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
 }
+
+func TestGetStatusSummary(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	weekAgo := today.AddDate(0, 0, -7)
+
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &weekAgo)
+	testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, &weekAgo)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &weekAgo)
+	testDB.CreateTestStreak(t, ctx, running.ID, today.AddDate(0, 0, -2), today)
+
+	summary, err := GetStatusSummary(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), summary.Done)
+	assert.Equal(t, int64(2), summary.Total)
+	assert.Equal(t, int64(1), summary.Remaining)
+	// smoking has been clean for 6 full days since creation
+	assert.Equal(t, int64(6), summary.BestStreak)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+// SetHabitCosts sets what one occurrence of a quit habit costs in money and minutes, 0 clears a value.
+func SetHabitCosts(appContext context.Context, habitName string, costPerOccurrence float64, minutesPerOccurrence int64) error {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return err
+	}
+	if habit.HabitType != store.HabitTypeQuit {
+		return &se.StreakrError{TerminalMsg: "cost and time per occurrence can only be set for quit habits"}
+	}
+	if costPerOccurrence < 0 || minutesPerOccurrence < 0 {
+		return &se.StreakrError{TerminalMsg: "cost and time per occurrence cannot be negative"}
+	}
+	habit.CostPerOccurrence = sql.NullFloat64{Float64: costPerOccurrence, Valid: costPerOccurrence > 0}
+	habit.MinutesPerOccurrence = sql.NullInt64{Int64: minutesPerOccurrence, Valid: minutesPerOccurrence > 0}
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	err = qtx.UpdateHabitCosts(appContext, generated.UpdateHabitCostsParams{
+		ID:                   habit.ID,
+		CostPerOccurrence:    habit.CostPerOccurrence,
+		MinutesPerOccurrence: habit.MinutesPerOccurrence,
+	})
+	if err != nil {
+		return err
+	}
+	if err = recordSyncOp(appContext, qtx, habitCostsSyncOp(habit)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetQuitHabitSavings computes the savings of a quit habit and its slip-ups for each of the past `weeks` weeks.
+func GetQuitHabitSavings(appContext context.Context, habit generated.Habit, weeks int) (*types.QuitHabitSavings, error) {
+	streaks, err := store.GetQueries().ListStreaksForHabit(appContext, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	slipups, err := store.GetQueries().ListSlipupsForHabit(appContext, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	return getQuitHabitSavings(habit, streaks, slipups, weeks, time.Now()), nil
+}
+
+func getQuitHabitSavings(habit generated.Habit, streaks []generated.Streak, slipups []generated.Slipup, weeks int, today time.Time) *types.QuitHabitSavings {
+	savings := &types.QuitHabitSavings{}
+	for _, clean := range getDailyCompletion(habit, streaks, today) {
+		if clean {
+			savings.CleanDays++
+		}
+	}
+	savings.MoneySaved = float64(savings.CleanDays) * habit.CostPerOccurrence.Float64
+	savings.MinutesSaved = savings.CleanDays * habit.MinutesPerOccurrence.Int64
+
+	// only show weeks the habit has been tracked for.
+	trackedWeeks := util.GetDayDiff(habit.CreatedAt, today)/7 + 1
+	savings.SlipupsPerWeek = make([]int64, min(weeks, trackedWeeks))
+
+	countsByDay := make(map[string]int64)
+	for _, slipup := range slipups {
+		countsByDay[slipup.SlipDate.Format(time.DateOnly)] = slipup.Count
+	}
+	for _, streak := range streaks {
+		// every streak_end of a quit habit is a slip-up day
+		count, ok := countsByDay[streak.StreakEnd.Format(time.DateOnly)]
+		if !ok {
+			count = 1
+		}
+		savings.TotalSlipups += count
+		idx := len(savings.SlipupsPerWeek) - 1 - util.GetDayDiff(streak.StreakEnd, today)/7
+		if idx >= 0 && idx < len(savings.SlipupsPerWeek) {
+			savings.SlipupsPerWeek[idx] += count
+		}
+	}
+	return savings
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQuitHabitSavings(t *testing.T) {
+	createdAt := time.Date(2025, 11, 1, 9, 0, 0, 0, time.Local)
+	today := time.Date(2025, 11, 20, 12, 0, 0, 0, time.Local)
+	habit := generated.Habit{
+		ID:                   1,
+		Name:                 "smoking",
+		HabitType:            store.HabitTypeQuit,
+		CreatedAt:            createdAt,
+		CostPerOccurrence:    sql.NullFloat64{Float64: 0.5, Valid: true},
+		MinutesPerOccurrence: sql.NullInt64{Int64: 5, Valid: true},
+	}
+	// slip-ups on nov 4 (3 times) and nov 18 (no count recorded, counts once)
+	streaks := []generated.Streak{
+		{HabitID: 1, StreakStart: time.Date(2025, 11, 2, 12, 0, 0, 0, time.Local), StreakEnd: time.Date(2025, 11, 4, 12, 0, 0, 0, time.Local)},
+		{HabitID: 1, StreakStart: time.Date(2025, 11, 5, 12, 0, 0, 0, time.Local), StreakEnd: time.Date(2025, 11, 18, 12, 0, 0, 0, time.Local)},
+	}
+	slipups := []generated.Slipup{
+		{HabitID: 1, SlipDate: time.Date(2025, 11, 4, 12, 0, 0, 0, time.Local), Count: 3},
+	}
+
+	savings := getQuitHabitSavings(habit, streaks, slipups, 8, today)
+	// nov 2-19 is 18 tracked days with 2 slip-up days
+	assert.Equal(t, int64(16), savings.CleanDays)
+	assert.InDelta(t, 8.0, savings.MoneySaved, 0.001)
+	assert.Equal(t, int64(80), savings.MinutesSaved)
+	assert.Equal(t, int64(4), savings.TotalSlipups)
+	// the habit is only 3 weeks old: nov 1-6, nov 7-13, nov 14-20
+	assert.Equal(t, []int64{3, 0, 1}, savings.SlipupsPerWeek)
+}
+
+func TestLogHabitsForTodayWithCount(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	createdAt := time.Now().AddDate(0, 0, -10)
+	habit := testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &createdAt)
+
+	_, err := LogHabitsForTodayWithCount(ctx, []string{"smoking"}, 0)
+	assert.Error(t, err)
+
+	_, err = LogHabitsForTodayWithCount(ctx, []string{"smoking"}, 3)
+	require.NoError(t, err)
+	// logging again on the same day adds to the count
+	_, err = LogHabitsForToday(ctx, []string{"smoking"})
+	require.NoError(t, err)
+
+	slipups, err := testDB.Queries.ListSlipupsForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, slipups, 1)
+	assert.Equal(t, int64(4), slipups[0].Count)
+
+	// unlogging the day drops its count as well
+	require.NoError(t, UnlogHabitForDate(ctx, "smoking", time.Now()))
+	slipups, err = testDB.Queries.ListSlipupsForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	assert.Empty(t, slipups)
+}
+
+func TestSetHabitCosts(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, nil)
+	testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, nil)
+
+	require.NoError(t, SetHabitCosts(ctx, "smoking", 0.5, 5))
+	habit, err := GetHabitByName(ctx, "smoking")
+	require.NoError(t, err)
+	assert.Equal(t, sql.NullFloat64{Float64: 0.5, Valid: true}, habit.CostPerOccurrence)
+	assert.Equal(t, sql.NullInt64{Int64: 5, Valid: true}, habit.MinutesPerOccurrence)
+
+	require.NoError(t, SetHabitCosts(ctx, "smoking", 0, 5))
+	habit, err = GetHabitByName(ctx, "smoking")
+	require.NoError(t, err)
+	assert.False(t, habit.CostPerOccurrence.Valid)
+
+	assert.Error(t, SetHabitCosts(ctx, "running", 1, 0), "improve habits have no cost")
+	assert.Error(t, SetHabitCosts(ctx, "smoking", -1, 0))
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+const reminderTimeLayout = "15:04"
+
+// ParseReminderTime validates a HH:MM reminder time and returns it in canonical form.
+func ParseReminderTime(reminderTime string) (string, error) {
+	t, err := time.Parse(reminderTimeLayout, reminderTime)
+	if err != nil {
+		return "", &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid time '%s': must be HH:MM (24h)", reminderTime)}
+	}
+	return t.Format(reminderTimeLayout), nil
+}
+
+// SetReminders replaces all reminder times of a habit.
+func SetReminders(appContext context.Context, habitName string, reminderTimes []string) error {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return err
+	}
+	canonicalTimes := make([]string, 0, len(reminderTimes))
+	for _, reminderTime := range reminderTimes {
+		canonicalTime, err := ParseReminderTime(reminderTime)
+		if err != nil {
+			return err
+		}
+		canonicalTimes = append(canonicalTimes, canonicalTime)
+	}
+
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	if err = qtx.DeleteRemindersForHabit(appContext, habit.ID); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, canonicalTime := range canonicalTimes {
+		if seen[canonicalTime] {
+			continue
+		}
+		seen[canonicalTime] = true
+		err = qtx.AddReminder(appContext, generated.AddReminderParams{
+			HabitID:  habit.ID,
+			RemindAt: canonicalTime,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func ClearReminders(appContext context.Context, habitName string) error {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return err
+	}
+	return store.GetQueries().DeleteRemindersForHabit(appContext, habit.ID)
+}
+
+// ListReminders returns the reminder times of every habit that has any, keyed by habit name.
+func ListReminders(appContext context.Context) (map[string][]string, error) {
+	habits, err := ListHabits(appContext)
+	if err != nil {
+		return nil, err
+	}
+	habitNames := make(map[int64]string)
+	for _, habit := range habits {
+		habitNames[habit.ID] = habit.Name
+	}
+	reminders, err := store.GetQueries().ListReminders(appContext)
+	if err != nil {
+		return nil, err
+	}
+	remindersByHabit := make(map[string][]string)
+	for _, reminder := range reminders {
+		name := habitNames[reminder.HabitID]
+		remindersByHabit[name] = append(remindersByHabit[name], reminder.RemindAt)
+	}
+	return remindersByHabit, nil
+}
+
+// isHabitLoggedOn reports whether a streak of habit covers day.
+func isHabitLoggedOn(appContext context.Context, habit generated.Habit, day time.Time) (bool, error) {
+	// streaks keep the time they were logged at, so the query covers the whole day
+	streaks, err := store.GetQueries().GetStreaksInRange(appContext, generated.GetStreaksInRangeParams{
+		StreakEnd:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()),
+		StreakStart: time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, -1, day.Location()),
+		HabitID:     habit.ID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(streaks) > 0, nil
+}
+
+// GetDueReminders returns improve habits that have a reminder time in (from, to] and weren't logged on
+// the day of that reminder. The window may cross midnight, e.g. a 23:50 reminder is due in (23:45, 00:00].
+// Habits without reminder times of their own use defaultTimes.
+func GetDueReminders(appContext context.Context, from, to time.Time, defaultTimes []string) ([]types.DueReminder, error) {
+	habits, err := ListHabits(appContext)
+	if err != nil {
+		return nil, err
+	}
+	dueReminders := make([]types.DueReminder, 0)
+	for _, habit := range habits {
+		if habit.HabitType != store.HabitTypeImprove {
+			continue
+		}
+		reminderTimes, err := store.GetQueries().ListRemindersForHabit(appContext, habit.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(reminderTimes) == 0 {
+			reminderTimes = defaultTimes
+		}
+		dueAt, dueDay, err := firstReminderIn(reminderTimes, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if dueAt == "" {
+			continue
+		}
+		logged, err := isHabitLoggedOn(appContext, habit, dueDay)
+		if err != nil {
+			return nil, err
+		}
+		if logged {
+			continue
+		}
+		dueReminders = append(dueReminders, types.DueReminder{
+			Habit:    habit,
+			RemindAt: dueAt,
+		})
+	}
+	sort.Slice(dueReminders, func(i, j int) bool {
+		return dueReminders[i].Habit.Name < dueReminders[j].Habit.Name
+	})
+	return dueReminders, nil
+}
+
+// firstReminderIn returns the first of reminderTimes that falls in (from, to] on from's or to's date,
+// with the day it falls on. It returns "" if none does.
+func firstReminderIn(reminderTimes []string, from, to time.Time) (string, time.Time, error) {
+	days := []time.Time{to}
+	if !util.IsSameDate(from, to) {
+		days = []time.Time{from.In(to.Location()), to}
+	}
+	for _, reminderTime := range reminderTimes {
+		t, err := time.Parse(reminderTimeLayout, reminderTime)
+		if err != nil {
+			return "", time.Time{}, &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid reminder time '%s': must be HH:MM (24h)", reminderTime)}
+		}
+		for _, day := range days {
+			remindAt := time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, to.Location())
+			if remindAt.After(from) && !remindAt.After(to) {
+				return reminderTime, remindAt, nil
+			}
+		}
+	}
+	return "", time.Time{}, nil
+}
+
+// IsInQuietHours reports whether now falls between start and end (HH:MM).
+// The window may wrap midnight, an empty start or end disables quiet hours.
+func IsInQuietHours(now time.Time, start, end string) (bool, error) {
+	if start == "" || end == "" {
+		return false, nil
+	}
+	startTime, err := time.Parse(reminderTimeLayout, start)
+	if err != nil {
+		return false, &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid quiet hours start '%s': must be HH:MM (24h)", start)}
+	}
+	endTime, err := time.Parse(reminderTimeLayout, end)
+	if err != nil {
+		return false, &se.StreakrError{TerminalMsg: fmt.Sprintf("invalid quiet hours end '%s': must be HH:MM (24h)", end)}
+	}
+	minuteOfDay := now.Hour()*60 + now.Minute()
+	startMinute := startTime.Hour()*60 + startTime.Minute()
+	endMinute := endTime.Hour()*60 + endTime.Minute()
+	if startMinute <= endMinute {
+		return minuteOfDay >= startMinute && minuteOfDay < endMinute, nil
+	}
+	return minuteOfDay >= startMinute || minuteOfDay < endMinute, nil
+}
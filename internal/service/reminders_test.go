@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetReminders(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, nil)
+
+	require.NoError(t, SetReminders(ctx, "running", []string{"19:30", "7:00", "19:30"}))
+	reminders, err := ListReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"07:00", "19:30"}, reminders["running"])
+
+	// setting again replaces previous times
+	require.NoError(t, SetReminders(ctx, "running", []string{"08:15"}))
+	reminders, err = ListReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"08:15"}, reminders["running"])
+
+	require.NoError(t, ClearReminders(ctx, "running"))
+	reminders, err = ListReminders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+
+	assert.Error(t, SetReminders(ctx, "running", []string{"25:00"}))
+	assert.Error(t, SetReminders(ctx, "nonexistent", []string{"07:00"}))
+}
+
+func TestGetDueReminders(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, nil)
+
+	remindAt := now.Add(-5 * time.Minute).Format("15:04")
+	if now.Add(-5*time.Minute).Day() != now.Day() {
+		t.Skip("reminder window crosses midnight")
+	}
+	require.NoError(t, SetReminders(ctx, "running", []string{remindAt}))
+
+	// reading falls back to default times, smoking is a quit habit and is never reminded
+	due, err := GetDueReminders(ctx, now.Add(-15*time.Minute), now, []string{remindAt})
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "reading", due[0].Habit.Name)
+	assert.Equal(t, "running", due[1].Habit.Name)
+
+	// outside the window nothing is due
+	due, err = GetDueReminders(ctx, now.Add(-2*time.Minute), now, []string{remindAt})
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	// logged habits are not reminded
+	testDB.CreateTestStreak(t, ctx, running.ID, now, now)
+	due, err = GetDueReminders(ctx, now.Add(-15*time.Minute), now, []string{remindAt})
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "reading", due[0].Habit.Name)
+}
+
+func TestGetDueReminders_AcrossMidnight(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, nil)
+	require.NoError(t, SetReminders(ctx, "running", []string{"23:50"}))
+	require.NoError(t, SetReminders(ctx, "reading", []string{"00:05"}))
+
+	// a run at midnight still fires yesterday's late reminders
+	due, err := GetDueReminders(ctx, midnight.Add(-15*time.Minute), midnight, nil)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "running", due[0].Habit.Name)
+	due, err = GetDueReminders(ctx, midnight.Add(-15*time.Minute), midnight.Add(15*time.Minute), nil)
+	require.NoError(t, err)
+	assert.Len(t, due, 2)
+
+	// a reminder is skipped if the habit was logged on its own day
+	yesterday := midnight.Add(-time.Hour)
+	testDB.CreateTestStreak(t, ctx, running.ID, yesterday, yesterday)
+	due, err = GetDueReminders(ctx, midnight.Add(-15*time.Minute), midnight.Add(15*time.Minute), nil)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "reading", due[0].Habit.Name)
+}
+
+func TestIsInQuietHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   string
+		start string
+		end   string
+		want  bool
+	}{
+		{name: "disabled", now: "23:00", start: "", end: "", want: false},
+		{name: "inside same day window", now: "13:00", start: "12:00", end: "14:00", want: true},
+		{name: "at end of window", now: "14:00", start: "12:00", end: "14:00", want: false},
+		{name: "inside window wrapping midnight", now: "23:30", start: "22:00", end: "07:00", want: true},
+		{name: "early morning inside wrapping window", now: "06:59", start: "22:00", end: "07:00", want: true},
+		{name: "outside wrapping window", now: "12:00", start: "22:00", end: "07:00", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse("15:04", tt.now)
+			require.NoError(t, err)
+			got, err := IsInQuietHours(now, tt.start, tt.end)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+const (
+	ReportPeriodWeek  = "week"
+	ReportPeriodMonth = "month"
+	ReportPeriodYear  = "year"
+)
+
+// getReportRange returns the week (from weekStart), month or year holding today and the one before it.
+func getReportRange(period string, today time.Time, weekStart time.Weekday) (start, end, previousStart, previousEnd time.Time, err error) {
+	today = util.GetNoonOf(today)
+	switch period {
+	case ReportPeriodWeek:
+		start = util.GetStartOfWeek(today, weekStart)
+		end = start.AddDate(0, 0, 6)
+		previousStart = start.AddDate(0, 0, -7)
+	case ReportPeriodMonth:
+		start = time.Date(today.Year(), today.Month(), 1, 12, 0, 0, 0, today.Location())
+		end = start.AddDate(0, 1, -1)
+		previousStart = start.AddDate(0, -1, 0)
+	case ReportPeriodYear:
+		start = time.Date(today.Year(), time.January, 1, 12, 0, 0, 0, today.Location())
+		end = start.AddDate(1, 0, -1)
+		previousStart = start.AddDate(-1, 0, 0)
+	default:
+		err = fmt.Errorf("invalid period %q, must be %s, %s or %s", period, ReportPeriodWeek, ReportPeriodMonth, ReportPeriodYear)
+		return
+	}
+	previousEnd = util.GetPrevDayOf(start)
+	return
+}
+
+// GetReport compares every habit over the period holding today with the period before it,
+// weeks start on weekStart.
+func GetReport(appContext context.Context, period string, weekStart time.Weekday) (*types.Report, error) {
+	today := time.Now()
+	start, end, previousStart, previousEnd, err := getReportRange(period, today, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	overallStats, err := GetOverallStats(appContext)
+	if err != nil {
+		return nil, err
+	}
+	report := &types.Report{
+		Period:        period,
+		Start:         start,
+		End:           end,
+		PreviousStart: previousStart,
+		PreviousEnd:   previousEnd,
+		Today:         today,
+		WeekStart:     weekStart,
+		Habits:        make([]types.HabitReport, 0, len(overallStats.HabitInfos)),
+	}
+	for _, habitInfo := range overallStats.HabitInfos {
+		current, err := GetHabitStatsForRange(appContext, habitInfo.Habit.Slug, start, end)
+		if err != nil {
+			return nil, err
+		}
+		previous, err := GetHabitStatsForRange(appContext, habitInfo.Habit.Slug, previousStart, previousEnd)
+		if err != nil {
+			return nil, err
+		}
+		habitReport := types.HabitReport{
+			HabitInfo:             habitInfo,
+			Done:                  current.TotalStreakDaysInRange,
+			Tracked:               current.TotalStreakDaysInRange + current.TotalMissesInRange,
+			Completion:            current.Completion(),
+			PreviousCompletion:    previous.Completion(),
+			HasPrevious:           previous.TotalStreakDaysInRange+previous.TotalMissesInRange > 0,
+			LongestStreak:         current.LongestStreak(),
+			PreviousLongestStreak: previous.LongestStreak(),
+			Heatmap:               current.Heatmap,
+		}
+		report.Habits = append(report.Habits, habitReport)
+	}
+
+	// habits not tracked yet in the period can't be best or worst
+	for i := range report.Habits {
+		habitReport := &report.Habits[i]
+		if habitReport.Tracked == 0 {
+			continue
+		}
+		if report.Best == nil || habitReport.Completion > report.Best.Completion {
+			report.Best = habitReport
+		}
+		if report.Worst == nil || habitReport.Completion < report.Worst.Completion {
+			report.Worst = habitReport
+		}
+	}
+	if report.Best == report.Worst {
+		report.Worst = nil
+	}
+	return report, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReportRange(t *testing.T) {
+	// a wednesday
+	today := time.Date(2026, 3, 4, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		period        string
+		start, end    string
+		previousStart string
+		previousEnd   string
+	}{
+		{ReportPeriodWeek, "2026-03-02", "2026-03-08", "2026-02-23", "2026-03-01"},
+		{ReportPeriodMonth, "2026-03-01", "2026-03-31", "2026-02-01", "2026-02-28"},
+		{ReportPeriodYear, "2026-01-01", "2026-12-31", "2025-01-01", "2025-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			start, end, previousStart, previousEnd, err := getReportRange(tt.period, today, time.Monday)
+			require.NoError(t, err)
+			assert.Equal(t, tt.start, start.Format(time.DateOnly))
+			assert.Equal(t, tt.end, end.Format(time.DateOnly))
+			assert.Equal(t, tt.previousStart, previousStart.Format(time.DateOnly))
+			assert.Equal(t, tt.previousEnd, previousEnd.Format(time.DateOnly))
+		})
+	}
+
+	// sundays belong to the week before, unless weeks start on sunday
+	sunday := time.Date(2026, 3, 8, 9, 0, 0, 0, time.Local)
+	start, _, _, _, err := getReportRange(ReportPeriodWeek, sunday, time.Monday)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-02", start.Format(time.DateOnly))
+	start, end, _, _, err := getReportRange(ReportPeriodWeek, sunday, time.Sunday)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-08", start.Format(time.DateOnly))
+	assert.Equal(t, "2026-03-14", end.Format(time.DateOnly))
+	start, _, _, _, err = getReportRange(ReportPeriodWeek, today, time.Saturday)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-28", start.Format(time.DateOnly))
+
+	_, _, _, _, err = getReportRange("day", today, time.Monday)
+	assert.Error(t, err)
+}
+
+func TestGetReport(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+	ctx := context.Background()
+	createdAt := time.Now().AddDate(0, 0, -60)
+	running := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "reading", "", store.HabitTypeImprove, &createdAt)
+	// logged every day since creation
+	testDB.CreateTestStreak(t, ctx, running.ID, createdAt, time.Now())
+
+	report, err := GetReport(ctx, ReportPeriodYear, time.Monday)
+	require.NoError(t, err)
+	require.Len(t, report.Habits, 2)
+	require.NotNil(t, report.Best)
+	require.NotNil(t, report.Worst)
+	assert.Equal(t, "running", report.Best.Habit.Name)
+	assert.Equal(t, "reading", report.Worst.Habit.Name)
+	assert.InDelta(t, 100, report.Best.Completion, 0.01)
+	assert.InDelta(t, 0, report.Worst.Completion, 0.01)
+	assert.Equal(t, report.Best.Done, report.Best.LongestStreak)
+	assert.Len(t, report.Best.Heatmap, len(report.Habits[1].Heatmap))
+
+	_, err = GetReport(ctx, "day", time.Monday)
+	assert.Error(t, err)
+}
+
+func TestHabitStatsForRangeLongestStreak(t *testing.T) {
+	assert.Equal(t, 0, types.HabitStatsForRange{}.LongestStreak())
+	assert.Equal(t, 3, types.HabitStatsForRange{Heatmap: []bool{true, false, true, true, true, false, true}}.LongestStreak())
+}
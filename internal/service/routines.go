@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/mattn/go-sqlite3"
+)
+
+// RoutinePrefix marks a routine name where habit names are expected, e.g. `streakr log @morning`.
+const RoutinePrefix = "@"
+
+func CreateRoutine(appContext context.Context, name string, habitNames []string) error {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, RoutinePrefix)))
+	if name == "" {
+		return &se.StreakrError{TerminalMsg: "routine name cannot be empty"}
+	}
+	if util.DisplayWidth(name) > 20 {
+		return &se.StreakrError{TerminalMsg: "routine name cannot be wider than 20 characters, emoji count as 2"}
+	}
+	// the schema caps names at 20 code points as well, which joined emoji and combining marks can exceed
+	if utf8.RuneCountInString(name) > 20 {
+		return &se.StreakrError{TerminalMsg: "routine name has too many emoji or accents, shorten it"}
+	}
+	if strings.ContainsAny(name, " \t,") {
+		return &se.StreakrError{TerminalMsg: fmt.Sprintf("routine name '%s' should be a single word", name)}
+	}
+
+	members := make([]string, 0, len(habitNames))
+	for _, habitName := range habitNames {
+		if strings.TrimSpace(habitName) == "" {
+			return &se.StreakrError{TerminalMsg: "habit name cannot be empty"}
+		}
+		habit, err := GetHabitByName(appContext, habitName)
+		if err != nil {
+			return err
+		}
+		// logging a quit habit records a slip-up, it can't be logged along with a routine
+		if habit.HabitType != store.HabitTypeImprove {
+			return &se.StreakrError{
+				TerminalMsg: fmt.Sprintf("%s is a quit habit, routines can only hold improve habits", habit.Name),
+				Kind:        se.KindValidation,
+			}
+		}
+		if !slices.Contains(members, habit.Slug) {
+			members = append(members, habit.Slug)
+		}
+	}
+	if len(members) == 0 {
+		return &se.StreakrError{TerminalMsg: "a routine needs at least one habit"}
+	}
+
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	routineID, err := qtx.CreateRoutine(appContext, name)
+	if err != nil {
+		if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return &se.StreakrError{TerminalMsg: fmt.Sprintf("Cannot create routine %s as it already exists", name), Kind: se.KindAlreadyExists}
+		}
+		return err
+	}
+	for i, habitName := range members {
+		err = qtx.AddRoutineHabit(appContext, generated.AddRoutineHabitParams{
+			RoutineID: routineID,
+			HabitName: habitName,
+			Position:  int64(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func DeleteRoutine(appContext context.Context, name string) error {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, RoutinePrefix)))
+	deleted, err := store.GetQueries().DeleteRoutine(appContext, name)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return &se.StreakrError{TerminalMsg: fmt.Sprintf("No routine with name %s", name), Kind: se.KindNotFound}
+	}
+	return nil
+}
+
+func ListRoutines(appContext context.Context) ([]types.Routine, error) {
+	rows, err := store.GetQueries().ListRoutineHabits(appContext)
+	if err != nil {
+		return nil, err
+	}
+	routines := make([]types.Routine, 0)
+	for _, row := range rows {
+		if len(routines) == 0 || routines[len(routines)-1].Name != row.Name {
+			routines = append(routines, types.Routine{Name: row.Name})
+		}
+		last := &routines[len(routines)-1]
+		last.HabitNames = append(last.HabitNames, row.HabitName)
+	}
+	return routines, nil
+}
+
+// ExpandRoutine returns the improve habits of a routine, failing if any member has since been deleted.
+// Quit habits, which routines created before they were refused may hold, are left out.
+func ExpandRoutine(appContext context.Context, name string) ([]string, error) {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, RoutinePrefix)))
+	habitNames, err := store.GetQueries().ListRoutineHabitsByName(appContext, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(habitNames) == 0 {
+		return nil, &se.StreakrError{TerminalMsg: fmt.Sprintf("No routine with name %s", name), Kind: se.KindNotFound}
+	}
+	improveHabitNames := make([]string, 0, len(habitNames))
+	for _, habitName := range habitNames {
+		habit, err := store.GetQueries().GetHabitBySlug(appContext, habitName)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, &se.StreakrError{
+				TerminalMsg: fmt.Sprintf(
+					"routine %s includes habit %s which no longer exists, recreate the routine to fix it",
+					name,
+					habitName,
+				),
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if habit.HabitType == store.HabitTypeImprove {
+			improveHabitNames = append(improveHabitNames, habitName)
+		}
+	}
+	if len(improveHabitNames) == 0 {
+		return nil, &se.StreakrError{
+			TerminalMsg: fmt.Sprintf("routine %s has no improve habits, quit habits have to be logged by name", name),
+			Kind:        se.KindNotFound,
+		}
+	}
+	return improveHabitNames, nil
+}
+
+// GetTodaysRoutineProgress counts the logged improve habits of every routine, routines without improve habits are skipped.
+func GetTodaysRoutineProgress(appContext context.Context) ([]types.RoutineProgress, error) {
+	routines, err := ListRoutines(appContext)
+	if err != nil {
+		return nil, err
+	}
+	progress := make([]types.RoutineProgress, 0, len(routines))
+	for _, routine := range routines {
+		routineProgress := types.RoutineProgress{Name: routine.Name}
+		for _, habitName := range routine.HabitNames {
+			habit, err := store.GetQueries().GetHabitBySlug(appContext, habitName)
+			if errors.Is(err, sql.ErrNoRows) {
+				// deleted members are reported by ExpandRoutine, here they just don't count
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if habit.HabitType != store.HabitTypeImprove {
+				continue
+			}
+			routineProgress.Total++
+			logged, err := isHabitLoggedOn(appContext, habit, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			if logged {
+				routineProgress.Done++
+			}
+		}
+		if routineProgress.Total > 0 {
+			progress = append(progress, routineProgress)
+		}
+	}
+	return progress, nil
+}
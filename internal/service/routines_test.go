@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndExpandRoutine(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	testDB.CreateTestHabit(t, ctx, "meditate", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "stretch", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "journal", "test", store.HabitTypeImprove, nil)
+
+	require.NoError(t, CreateRoutine(ctx, "Morning", []string{"meditate", " stretch", "journal", "meditate"}))
+
+	habitNames, err := ExpandRoutine(ctx, "@morning")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"meditate", "stretch", "journal"}, habitNames)
+
+	assert.Error(t, CreateRoutine(ctx, "morning", []string{"meditate"}), "duplicate routine")
+	assert.Error(t, CreateRoutine(ctx, "evening", []string{"nonexistent"}), "unknown member")
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, nil)
+	err = CreateRoutine(ctx, "evening", []string{"stretch", "smoking"})
+	assert.ErrorContains(t, err, "quit habit")
+	assert.Equal(t, se.KindValidation, se.KindOf(err))
+	// names are limited by display width like habit names, emoji count as 2
+	assert.NoError(t, CreateRoutine(ctx, "🧘🧘🧘🧘🧘🧘🧘🧘🧘🧘", []string{"stretch"}))
+	assert.Error(t, CreateRoutine(ctx, "🧘🧘🧘🧘🧘🧘🧘🧘🧘🧘🧘", []string{"stretch"}))
+	require.NoError(t, DeleteRoutine(ctx, "🧘🧘🧘🧘🧘🧘🧘🧘🧘🧘"))
+	_, err = ExpandRoutine(ctx, "evening")
+	assert.Error(t, err)
+
+	// deleting a member habit makes the routine invalid instead of silently shrinking it
+	require.NoError(t, DeleteHabits(ctx, []string{"journal"}))
+	_, err = ExpandRoutine(ctx, "morning")
+	assert.ErrorContains(t, err, "journal")
+
+	require.NoError(t, DeleteRoutine(ctx, "morning"))
+	routines, err := ListRoutines(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, routines)
+	assert.Error(t, DeleteRoutine(ctx, "morning"))
+}
+
+func TestGetTodaysRoutineProgress(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -5)
+	meditate := testDB.CreateTestHabit(t, ctx, "meditate", "test", store.HabitTypeImprove, &createdAt)
+	stretch := testDB.CreateTestHabit(t, ctx, "stretch", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "journal", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &createdAt)
+	testDB.CreateTestStreak(t, ctx, meditate.ID, today, today)
+	testDB.CreateTestStreak(t, ctx, stretch.ID, today.AddDate(0, 0, -2), today)
+
+	// routines created before quit habits were refused may still hold them
+	addLegacyRoutine := func(name string, habitNames ...string) {
+		routineID, err := store.GetQueries().CreateRoutine(ctx, name)
+		require.NoError(t, err)
+		for i, habitName := range habitNames {
+			require.NoError(t, store.GetQueries().AddRoutineHabit(ctx, generated.AddRoutineHabitParams{
+				RoutineID: routineID, HabitName: habitName, Position: int64(i),
+			}))
+		}
+	}
+	addLegacyRoutine("morning", "meditate", "stretch", "journal", "smoking")
+	addLegacyRoutine("night", "smoking")
+
+	// logging a routine never logs its quit habits
+	habitNames, err := ExpandRoutine(ctx, "morning")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"meditate", "stretch", "journal"}, habitNames)
+	_, err = ExpandRoutine(ctx, "night")
+	assert.ErrorContains(t, err, "no improve habits")
+
+	progress, err := GetTodaysRoutineProgress(ctx)
+	require.NoError(t, err)
+	// quit habits are not counted, so the night routine has nothing to report
+	require.Len(t, progress, 1)
+	assert.Equal(t, "morning", progress[0].Name)
+	assert.Equal(t, int64(2), progress[0].Done)
+	assert.Equal(t, int64(3), progress[0].Total)
+}
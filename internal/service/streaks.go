@@ -4,15 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"slices"
+	"sort"
 	"time"
 
+	"github.com/Atharva21/streakr/internal/events"
 	"github.com/Atharva21/streakr/internal/store"
 	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
 	"github.com/Atharva21/streakr/internal/types"
 	"github.com/Atharva21/streakr/internal/util"
 )
 
 func LogHabitsForToday(appContext context.Context, habitNames []string) (bool, error) {
+	return LogHabitsForTodayWithCount(appContext, habitNames, 1)
+}
+
+// LogHabitsForTodayWithCount logs habits like LogHabitsForToday, recording slipupCount occurrences
+// for quit habits. Logging a quit habit again on the same day adds to today's occurrences.
+func LogHabitsForTodayWithCount(appContext context.Context, habitNames []string, slipupCount int64) (bool, error) {
+	if slipupCount < 1 {
+		return false, &se.StreakrError{TerminalMsg: "slip-up count must be at least 1"}
+	}
 	habitsToLogToday := make([]generated.Habit, 0)
 	allQuittingHabits := true
 	for _, habitName := range habitNames {
@@ -28,101 +41,194 @@ func LogHabitsForToday(appContext context.Context, habitNames []string) (bool, e
 	today := time.Now()
 	yesterday := util.GetPrevDayOf(today)
 	for _, habit := range habitsToLogToday {
-		latestStreak, err := store.GetQueries().GetLatestStreakForHabit(appContext, habit.ID)
+		streakBefore, err := getCurrentStreakForHabit(appContext, habit)
+		if err != nil {
+			return allQuittingHabits, err
+		}
+		latestStreakBefore, err := store.GetQueries().GetLatestStreakForHabit(appContext, habit.ID)
+		hadStreakBefore := err == nil
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return allQuittingHabits, err
+		}
+		logged, err := recordTodaysLog(appContext, habit, today, yesterday, slipupCount)
+		if err != nil {
+			return allQuittingHabits, err
+		}
+		if !logged {
+			continue
+		}
+		streakAfter, err := getCurrentStreakForHabit(appContext, habit)
 		if err != nil {
-			if !errors.Is(err, sql.ErrNoRows) {
-				return allQuittingHabits, err
+			return allQuittingHabits, err
+		}
+		publishLogEvents(appContext, habit, int64(streakBefore), int64(streakAfter), latestStreakBefore, hadStreakBefore, yesterday)
+	}
+	return allQuittingHabits, nil
+}
+
+// recordTodaysLog logs a single habit for today together with its slip-ups and sync ops in one transaction,
+// returns false if it was already logged today.
+func recordTodaysLog(appContext context.Context, habit generated.Habit, today, yesterday time.Time, slipupCount int64) (bool, error) {
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	logged, err := logHabitForToday(appContext, qtx, habit, today, yesterday)
+	if err != nil {
+		return false, err
+	}
+	if habit.HabitType == store.HabitTypeQuit {
+		err = qtx.AddSlipupCount(appContext, generated.AddSlipupCountParams{
+			HabitID:  habit.ID,
+			SlipDate: util.GetNoonOf(today),
+			Count:    slipupCount,
+		})
+		if err != nil {
+			return false, err
+		}
+		// every slip-up changes today's count, so it is shared even if the day was already logged.
+		count, err := getSlipupCountOnDate(appContext, qtx, habit, today)
+		if err != nil {
+			return false, err
+		}
+		if err = recordSyncOp(appContext, qtx, daySyncOp(habit, today, true, count)); err != nil {
+			return false, err
+		}
+	} else if logged {
+		if err = recordSyncOp(appContext, qtx, daySyncOp(habit, today, true, 1)); err != nil {
+			return false, err
+		}
+	}
+	return logged, tx.Commit()
+}
+
+// logHabitForToday writes today's log for a single habit, returns false if it was already logged today.
+func logHabitForToday(appContext context.Context, q *generated.Queries, habit generated.Habit, today, yesterday time.Time) (bool, error) {
+	latestStreak, err := q.GetLatestStreakForHabit(appContext, habit.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return false, err
+		}
+		// first time log. for improvement habits, streak_start and end should be the same day.
+		// for quitting habits, streak_start should be habit.Created_at + 1, and streak_end should be y'day
+		// (with y'day being >= created_at + 1)
+		if habit.HabitType == store.HabitTypeImprove {
+			_, err = q.AddStreak(appContext, generated.AddStreakParams{
+				HabitID:     habit.ID,
+				StreakStart: today,
+				StreakEnd:   today,
+			})
+			if err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+		// logic for first log of quitting habits here
+		if util.IsSameDate(habit.CreatedAt, today) || util.IsSameDate(habit.CreatedAt, yesterday) {
+			_, err = q.AddStreak(appContext, generated.AddStreakParams{
+				HabitID:     habit.ID,
+				StreakStart: today,
+				StreakEnd:   today,
+			})
+			if err != nil {
+				return false, err
 			}
-			// first time log. for improvement habits, streak_start and end should be the same day.
-			// for quitting habits, streak_start should be habit.Created_at + 1, and streak_end should be y'day
-			// (with y'day being >= created_at + 1)
-			if habit.HabitType == store.HabitTypeImprove {
-				_, err = store.GetQueries().AddStreak(appContext, generated.AddStreakParams{
+			return true, nil
+		}
+		_, err = q.AddStreak(appContext, generated.AddStreakParams{
+			HabitID:     habit.ID,
+			StreakStart: util.GetNextDayOf(habit.CreatedAt),
+			StreakEnd:   today,
+		})
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	} else {
+		// logic for subsequent logs both improvement and quitting
+
+		if util.IsSameDate(latestStreak.StreakEnd, today) {
+			// handle duplicate logging. (skip for now)
+			return false, nil
+		}
+
+		if habit.HabitType == store.HabitTypeImprove {
+			// for improvement habits, if latest streak is of y'day update it to today. else add new streak
+			if util.IsSameDate(latestStreak.StreakEnd, yesterday) {
+				err = q.UpdateStreakEnd(appContext, generated.UpdateStreakEndParams{
+					ID:        latestStreak.ID,
+					StreakEnd: today,
+				})
+				if err != nil {
+					return false, err
+				}
+			} else {
+				_, err = q.AddStreak(appContext, generated.AddStreakParams{
 					HabitID:     habit.ID,
 					StreakStart: today,
 					StreakEnd:   today,
 				})
 				if err != nil {
-					return allQuittingHabits, err
+					return false, err
 				}
-				continue
 			}
-			// logic for first log of quitting habits here
-			if util.IsSameDate(habit.CreatedAt, today) || util.IsSameDate(habit.CreatedAt, yesterday) {
-				_, err = store.GetQueries().AddStreak(appContext, generated.AddStreakParams{
+		} else {
+			// for quitting habits, if latest.end == y'day, we do a today->today log
+			// else we log from latest.end+1->today
+			if util.IsSameDate(latestStreak.StreakEnd, yesterday) {
+				_, err = q.AddStreak(appContext, generated.AddStreakParams{
 					HabitID:     habit.ID,
 					StreakStart: today,
 					StreakEnd:   today,
 				})
 				if err != nil {
-					return allQuittingHabits, err
-				}
-				continue
-			}
-			_, err = store.GetQueries().AddStreak(appContext, generated.AddStreakParams{
-				HabitID:     habit.ID,
-				StreakStart: util.GetNextDayOf(habit.CreatedAt),
-				StreakEnd:   today,
-			})
-			if err != nil {
-				return allQuittingHabits, err
-			}
-			continue
-		} else {
-			// logic for subsequent logs both improvement and quitting
-
-			if util.IsSameDate(latestStreak.StreakEnd, today) {
-				// handle duplicate logging. (skip for now)
-				continue
-			}
-
-			if habit.HabitType == store.HabitTypeImprove {
-				// for improvement habits, if latest streak is of y'day update it to today. else add new streak
-				if util.IsSameDate(latestStreak.StreakEnd, yesterday) {
-					err = store.GetQueries().UpdateStreakEnd(appContext, generated.UpdateStreakEndParams{
-						ID:        latestStreak.ID,
-						StreakEnd: today,
-					})
-					if err != nil {
-						return allQuittingHabits, err
-					}
-				} else {
-					_, err = store.GetQueries().AddStreak(appContext, generated.AddStreakParams{
-						HabitID:     habit.ID,
-						StreakStart: today,
-						StreakEnd:   today,
-					})
-					if err != nil {
-						return allQuittingHabits, err
-					}
+					return false, err
 				}
 			} else {
-				// for quitting habits, if latest.end == y'day, we do a today->today log
-				// else we log from latest.end+1->today
-				if util.IsSameDate(latestStreak.StreakEnd, yesterday) {
-					_, err = store.GetQueries().AddStreak(appContext, generated.AddStreakParams{
-						HabitID:     habit.ID,
-						StreakStart: today,
-						StreakEnd:   today,
-					})
-					if err != nil {
-						return allQuittingHabits, err
-					}
-				} else {
-					_, err = store.GetQueries().AddStreak(appContext, generated.AddStreakParams{
-						HabitID:     habit.ID,
-						StreakStart: util.GetNextDayOf(latestStreak.StreakEnd),
-						StreakEnd:   today,
-					})
-					if err != nil {
-						return allQuittingHabits, err
-					}
+				_, err = q.AddStreak(appContext, generated.AddStreakParams{
+					HabitID:     habit.ID,
+					StreakStart: util.GetNextDayOf(latestStreak.StreakEnd),
+					StreakEnd:   today,
+				})
+				if err != nil {
+					return false, err
 				}
 			}
+		}
+
+	}
+	return true, nil
+}
 
+// publishLogEvents emits the events for a habit that was just logged for today.
+func publishLogEvents(appContext context.Context, habit generated.Habit, streakBefore, streakAfter int64, latestStreakBefore generated.Streak, hadStreakBefore bool, yesterday time.Time) {
+	habitPayload := events.HabitPayload{
+		Name:        habit.Name,
+		Description: habit.Description.String,
+		HabitType:   habit.HabitType,
+	}
+	if habit.HabitType == store.HabitTypeQuit {
+		events.Publish(appContext, events.Event{Type: events.SlipupLogged, Habit: habitPayload, CurrentStreak: streakAfter})
+		if streakBefore > 0 {
+			events.Publish(appContext, events.Event{Type: events.StreakBroken, Habit: habitPayload, CurrentStreak: streakAfter, PreviousStreak: streakBefore})
 		}
+		return
+	}
+	events.Publish(appContext, events.Event{Type: events.HabitLogged, Habit: habitPayload, CurrentStreak: streakAfter})
+	if hadStreakBefore && util.CompareDate(latestStreakBefore.StreakEnd, yesterday) == 1 {
+		// the previous streak ended before yesterday, today's log starts over.
+		events.Publish(appContext, events.Event{
+			Type:           events.StreakBroken,
+			Habit:          habitPayload,
+			CurrentStreak:  streakAfter,
+			PreviousStreak: int64(util.GetDayDiff(latestStreakBefore.StreakStart, latestStreakBefore.StreakEnd) + 1),
+		})
+	}
+	if streakAfter > streakBefore && slices.Contains(events.StreakMilestones, streakAfter) {
+		events.Publish(appContext, events.Event{Type: events.StreakMilestone, Habit: habitPayload, CurrentStreak: streakAfter, Milestone: streakAfter})
 	}
-	return allQuittingHabits, nil
 }
 
 func getHabitInfoForHabit(appContext context.Context, habit generated.Habit) (*types.HabitInfo, error) {
@@ -156,12 +262,21 @@ func getHabitInfoForHabit(appContext context.Context, habit generated.Habit) (*t
 		totalStreakDays += currentStreak
 	}
 	totalMissedDays := daysSinceHabitCreation - totalStreakDays
+	strengthSeries, err := getStrengthSeriesForHabit(appContext, habit)
+	if err != nil {
+		return nil, err
+	}
+	strength := 0.0
+	if len(strengthSeries) > 0 {
+		strength = strengthSeries[len(strengthSeries)-1] * 100
+	}
 	return &types.HabitInfo{
 		Habit:              habit,
 		CurrentStreak:      currentStreak,
 		MaxStreak:          pastMaxStreak,
 		TotalPerformedDays: totalStreakDays,
 		TotalMissedDays:    totalMissedDays,
+		Strength:           strength,
 	}, nil
 }
 
@@ -219,9 +334,12 @@ func GetHabitStatsForRange(appContext context.Context, habitName string, startDa
 	if err != nil {
 		return nil, err
 	}
+	// streaks keep the time they were logged at, so the query covers the whole first and last day
+	firstInstant := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	lastInstant := time.Date(endDate.Year(), endDate.Month(), endDate.Day()+1, 0, 0, 0, -1, endDate.Location())
 	streaksLst, err := store.GetQueries().GetStreaksInRange(appContext, generated.GetStreaksInRangeParams{
-		StreakEnd:   startDate,
-		StreakStart: endDate,
+		StreakEnd:   firstInstant,
+		StreakStart: lastInstant,
 		HabitID:     habit.ID,
 	})
 	if err != nil {
@@ -236,9 +354,11 @@ func GetHabitStatsForRange(appContext context.Context, habitName string, startDa
 		// Today is not counted as completed yet since the day hasn't passed
 		today := time.Now()
 		yesterday := util.GetPrevDayOf(today)
+		// the creation day itself is not counted as clean
 		effectiveStart := startDate
-		if util.CompareDate(habit.CreatedAt, startDate) == -1 {
-			effectiveStart = habit.CreatedAt
+		habitStart := util.GetNextDayOf(habit.CreatedAt)
+		if util.CompareDate(habitStart, startDate) == -1 {
+			effectiveStart = habitStart
 		}
 		effectiveEnd := endDate
 		// Don't count today or future dates as completed
@@ -345,15 +465,211 @@ func GetOverallStats(appContext context.Context) (*types.OverallStats, error) {
 	if err != nil {
 		return nil, err
 	}
+	return getOverallStatsForHabits(appContext, habits)
+}
+
+// GetOverallStatsByTag is GetOverallStats limited to habits carrying the tag.
+func GetOverallStatsByTag(appContext context.Context, tag string) (*types.OverallStats, error) {
+	habits, err := ListHabitsByTag(appContext, tag)
+	if err != nil {
+		return nil, err
+	}
+	return getOverallStatsForHabits(appContext, habits)
+}
+
+func getOverallStatsForHabits(appContext context.Context, habits []generated.Habit) (*types.OverallStats, error) {
 	habitInfos := make([]types.HabitInfo, 0)
+	subtotalsByTag := make(map[string]*types.TagSubtotal)
 	for _, habit := range habits {
 		habitInfo, err := getHabitInfoForHabit(appContext, habit)
 		if err != nil {
 			return nil, err
 		}
+		habitInfo.Tags, err = GetTagsForHabit(appContext, habit)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range habitInfo.Tags {
+			subtotal, ok := subtotalsByTag[tag]
+			if !ok {
+				subtotal = &types.TagSubtotal{Tag: tag}
+				subtotalsByTag[tag] = subtotal
+			}
+			subtotal.HabitCount++
+			subtotal.TotalPerformedDays += habitInfo.TotalPerformedDays
+			subtotal.TotalMissedDays += habitInfo.TotalMissedDays
+			subtotal.Strength += habitInfo.Strength
+		}
 		habitInfos = append(habitInfos, *habitInfo)
 	}
+	tagSubtotals := make([]types.TagSubtotal, 0, len(subtotalsByTag))
+	for _, subtotal := range subtotalsByTag {
+		subtotal.Strength /= float64(subtotal.HabitCount)
+		tagSubtotals = append(tagSubtotals, *subtotal)
+	}
+	sort.Slice(tagSubtotals, func(i, j int) bool {
+		return tagSubtotals[i].Tag < tagSubtotals[j].Tag
+	})
 	return &types.OverallStats{
-		HabitInfos: habitInfos,
+		HabitInfos:   habitInfos,
+		TagSubtotals: tagSubtotals,
 	}, nil
 }
+
+// setHabitLoggedOnDate logs or unlogs a habit on any day between its creation and today.
+func setHabitLoggedOnDate(appContext context.Context, habitName string, date time.Time, logged bool) error {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return err
+	}
+	if util.CompareDate(date, time.Now()) == -1 {
+		return &se.StreakrError{TerminalMsg: "Cannot log a habit for a future date"}
+	}
+	if util.CompareDate(habit.CreatedAt, date) == -1 {
+		return &se.StreakrError{TerminalMsg: "Cannot log a habit before its creation date"}
+	}
+	// keep the time of day away from midnight so DATE() conversions in sqlite can't shift the day.
+	date = util.GetNoonOf(date)
+	streakBefore, err := getCurrentStreakForHabit(appContext, habit)
+	if err != nil {
+		return err
+	}
+
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	changed, err := writeHabitLoggedOnDate(appContext, qtx, habit, date, logged)
+	if err != nil || !changed {
+		return err
+	}
+	if habit.HabitType == store.HabitTypeQuit {
+		if logged {
+			err = qtx.AddSlipupCount(appContext, generated.AddSlipupCountParams{HabitID: habit.ID, SlipDate: date, Count: 1})
+		} else {
+			err = qtx.DeleteSlipup(appContext, generated.DeleteSlipupParams{HabitID: habit.ID, SlipDate: date})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = recordSyncOp(appContext, qtx, daySyncOp(habit, date, logged, 1)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	streakAfter, err := getCurrentStreakForHabit(appContext, habit)
+	if err != nil {
+		return err
+	}
+	publishDateLogEvents(appContext, habit, int64(streakBefore), int64(streakAfter), logged)
+	return nil
+}
+
+// publishDateLogEvents emits the events for a habit that was just logged or unlogged on some day.
+func publishDateLogEvents(appContext context.Context, habit generated.Habit, streakBefore, streakAfter int64, logged bool) {
+	habitPayload := events.HabitPayload{
+		Name:        habit.Name,
+		Description: habit.Description.String,
+		HabitType:   habit.HabitType,
+	}
+	if !logged {
+		events.Publish(appContext, events.Event{Type: events.HabitUnlogged, Habit: habitPayload, CurrentStreak: streakAfter, PreviousStreak: streakBefore})
+		return
+	}
+	if habit.HabitType == store.HabitTypeQuit {
+		events.Publish(appContext, events.Event{Type: events.SlipupLogged, Habit: habitPayload, CurrentStreak: streakAfter})
+		if streakAfter < streakBefore {
+			events.Publish(appContext, events.Event{Type: events.StreakBroken, Habit: habitPayload, CurrentStreak: streakAfter, PreviousStreak: streakBefore})
+		}
+		return
+	}
+	events.Publish(appContext, events.Event{Type: events.HabitLogged, Habit: habitPayload, CurrentStreak: streakAfter})
+	if streakAfter > streakBefore && slices.Contains(events.StreakMilestones, streakAfter) {
+		events.Publish(appContext, events.Event{Type: events.StreakMilestone, Habit: habitPayload, CurrentStreak: streakAfter, Milestone: streakAfter})
+	}
+}
+
+// writeHabitLoggedOnDate rebuilds the streaks of habit with date logged or not, slip-up counts are left to the caller.
+// It returns false if date already was in that state.
+func writeHabitLoggedOnDate(appContext context.Context, q *generated.Queries, habit generated.Habit, date time.Time, logged bool) (bool, error) {
+	streaks, err := q.ListStreaksForHabit(appContext, habit.ID)
+	if err != nil {
+		return false, err
+	}
+
+	loggedDays := make([]time.Time, 0)
+	alreadyLogged := false
+	for _, day := range store.LoggedDays(habit, streaks) {
+		if util.IsSameDate(day, date) {
+			alreadyLogged = true
+			if !logged {
+				continue
+			}
+		}
+		loggedDays = append(loggedDays, day)
+	}
+	if alreadyLogged == logged {
+		return false, nil
+	}
+	if logged {
+		loggedDays = append(loggedDays, date)
+	}
+	sort.Slice(loggedDays, func(i, j int) bool {
+		return util.CompareDate(loggedDays[i], loggedDays[j]) == 1
+	})
+
+	if err = replaceStreaks(appContext, q, streaks, store.BuildStreaks(habit, loggedDays)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// replaceStreaks turns the streak rows of a habit into rebuilt, reusing the row of the first old streak
+// each new one overlaps so streak ids, and the calendar events keyed by them, survive merges and splits.
+func replaceStreaks(appContext context.Context, q *generated.Queries, old []generated.Streak, rebuilt []generated.AddStreakParams) error {
+	reused := make(map[int64]bool)
+	for _, streak := range rebuilt {
+		i := slices.IndexFunc(old, func(o generated.Streak) bool {
+			return !reused[o.ID] &&
+				util.CompareDate(o.StreakStart, streak.StreakEnd) >= 0 &&
+				util.CompareDate(streak.StreakStart, o.StreakEnd) >= 0
+		})
+		if i == -1 {
+			if _, err := q.AddStreak(appContext, streak); err != nil {
+				return err
+			}
+			continue
+		}
+		reused[old[i].ID] = true
+		if util.IsSameDate(old[i].StreakStart, streak.StreakStart) && util.IsSameDate(old[i].StreakEnd, streak.StreakEnd) {
+			continue
+		}
+		err := q.UpdateStreak(appContext, generated.UpdateStreakParams{StreakStart: streak.StreakStart, StreakEnd: streak.StreakEnd, ID: old[i].ID})
+		if err != nil {
+			return err
+		}
+	}
+	for _, streak := range old {
+		if reused[streak.ID] {
+			continue
+		}
+		if err := q.DeleteStreakByID(appContext, streak.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogHabitForDate logs a habit on a given date, it is a no-op if the habit is already logged that day.
+func LogHabitForDate(appContext context.Context, habitName string, date time.Time) error {
+	return setHabitLoggedOnDate(appContext, habitName, date, true)
+}
+
+// UnlogHabitForDate removes the log of a habit on a given date, it is a no-op if the habit isn't logged that day.
+func UnlogHabitForDate(appContext context.Context, habitName string, date time.Time) error {
+	return setHabitLoggedOnDate(appContext, habitName, date, false)
+}
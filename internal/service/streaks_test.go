@@ -2,10 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/Atharva21/streakr/internal/events"
 	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -391,6 +397,35 @@ func TestGetHabitStatsForRange_ImproveHabit(t *testing.T) {
 	assert.True(t, stats.Heatmap[9])  // Nov 10
 }
 
+func TestGetHabitStatsForRange_DaysAtNoon(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+
+	createdAt := time.Date(2025, 11, 1, 0, 0, 0, 0, time.Local)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+
+	// logged early on Nov 3 and late on Nov 10
+	testDB.CreateTestStreak(t, ctx, habit.ID,
+		time.Date(2025, 11, 3, 3, 15, 0, 0, time.Local),
+		time.Date(2025, 11, 3, 3, 15, 0, 0, time.Local))
+	testDB.CreateTestStreak(t, ctx, habit.ID,
+		time.Date(2025, 11, 10, 22, 0, 0, 0, time.Local),
+		time.Date(2025, 11, 10, 22, 0, 0, 0, time.Local))
+
+	// ranges from the date parser are at noon
+	stats, err := GetHabitStatsForRange(ctx, "running",
+		time.Date(2025, 11, 3, 12, 0, 0, 0, time.Local),
+		time.Date(2025, 11, 10, 12, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, stats.TotalStreakDaysInRange)
+	assert.Len(t, stats.Heatmap, 8)
+	assert.True(t, stats.Heatmap[0])
+	assert.True(t, stats.Heatmap[7])
+}
+
 func TestGetHabitStatsForRange_QuitHabit_NoLogs(t *testing.T) {
 	testDB := SetupTestDB(t)
 	defer testDB.Cleanup()
@@ -473,3 +508,238 @@ func isSameDay(t1, t2 time.Time) bool {
 	y2, m2, d2 := t2.Date()
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
+
+func TestLogHabitForDate_ImproveHabit(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -10)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestStreak(t, ctx, habit.ID, today.AddDate(0, 0, -5), today.AddDate(0, 0, -4))
+
+	// filling the gap day merges adjacent ranges
+	require.NoError(t, LogHabitForDate(ctx, "running", today.AddDate(0, 0, -3)))
+	require.NoError(t, LogHabitForDate(ctx, "running", today.AddDate(0, 0, -2)))
+	streaks, err := testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+	assert.True(t, isSameDay(streaks[0].StreakStart, today.AddDate(0, 0, -5)))
+	assert.True(t, isSameDay(streaks[0].StreakEnd, today.AddDate(0, 0, -2)))
+
+	// logging twice is a no-op
+	require.NoError(t, LogHabitForDate(ctx, "running", today.AddDate(0, 0, -2)))
+
+	// unlogging a day in the middle splits the range
+	require.NoError(t, UnlogHabitForDate(ctx, "running", today.AddDate(0, 0, -4)))
+	streaks, err = testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 2)
+	assert.True(t, isSameDay(streaks[0].StreakEnd, today.AddDate(0, 0, -5)))
+	assert.True(t, isSameDay(streaks[1].StreakStart, today.AddDate(0, 0, -3)))
+
+	assert.Error(t, LogHabitForDate(ctx, "running", today.AddDate(0, 0, 1)))
+	assert.Error(t, LogHabitForDate(ctx, "running", createdAt.AddDate(0, 0, -1)))
+}
+
+func TestLogHabitForDate_QuitHabit(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -10)
+	habit := testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &createdAt)
+
+	require.NoError(t, LogHabitForDate(ctx, "smoking", today.AddDate(0, 0, -3)))
+	require.NoError(t, LogHabitForDate(ctx, "smoking", today.AddDate(0, 0, -6)))
+	streaks, err := testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 2)
+	// clean days run from the day after creation up to the first slip-up
+	assert.True(t, isSameDay(streaks[0].StreakStart, createdAt.AddDate(0, 0, 1)))
+	assert.True(t, isSameDay(streaks[0].StreakEnd, today.AddDate(0, 0, -6)))
+	assert.True(t, isSameDay(streaks[1].StreakStart, today.AddDate(0, 0, -5)))
+	assert.True(t, isSameDay(streaks[1].StreakEnd, today.AddDate(0, 0, -3)))
+
+	require.NoError(t, UnlogHabitForDate(ctx, "smoking", today.AddDate(0, 0, -6)))
+	streaks, err = testDB.Queries.ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+	assert.True(t, isSameDay(streaks[0].StreakStart, createdAt.AddDate(0, 0, 1)))
+	assert.True(t, isSameDay(streaks[0].StreakEnd, today.AddDate(0, 0, -3)))
+}
+
+func TestLogHabitsForToday_PublishesEvents(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+	events.ResetHandlersForTesting()
+	defer events.ResetHandlersForTesting()
+
+	published := make([]events.Event, 0)
+	var mu sync.Mutex
+	events.RegisterHandler(func(ctx context.Context, event events.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, event)
+		return errors.New("handler failures must not fail logging")
+	})
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -20)
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	reading := testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &createdAt)
+	// running reaches a 7 day streak today, reading had a streak that ended 3 days ago
+	testDB.CreateTestStreak(t, ctx, running.ID, today.AddDate(0, 0, -6), today.AddDate(0, 0, -1))
+	testDB.CreateTestStreak(t, ctx, reading.ID, today.AddDate(0, 0, -5), today.AddDate(0, 0, -3))
+
+	_, err := LogHabitsForToday(ctx, []string{"running", "reading", "smoking"})
+	require.NoError(t, err)
+
+	eventTypes := make(map[string][]events.Event)
+	for _, event := range published {
+		eventTypes[event.Type] = append(eventTypes[event.Type], event)
+	}
+	require.Len(t, eventTypes[events.HabitLogged], 2)
+	require.Len(t, eventTypes[events.StreakMilestone], 1)
+	assert.Equal(t, "running", eventTypes[events.StreakMilestone][0].Habit.Name)
+	assert.Equal(t, int64(7), eventTypes[events.StreakMilestone][0].Milestone)
+	require.Len(t, eventTypes[events.SlipupLogged], 1)
+	require.Len(t, eventTypes[events.StreakBroken], 2)
+	for _, event := range eventTypes[events.StreakBroken] {
+		switch event.Habit.Name {
+		case "reading":
+			assert.Equal(t, int64(3), event.PreviousStreak)
+		case "smoking":
+			assert.Equal(t, int64(19), event.PreviousStreak)
+		default:
+			t.Errorf("unexpected streak.broken for %s", event.Habit.Name)
+		}
+	}
+
+	// logging again the same day publishes nothing
+	published = published[:0]
+	_, err = LogHabitsForToday(ctx, []string{"running"})
+	require.NoError(t, err)
+	assert.Empty(t, published)
+}
+
+func TestLogHabitForDate_PublishesEvents(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+	events.ResetHandlersForTesting()
+	defer events.ResetHandlersForTesting()
+
+	published := make([]events.Event, 0)
+	var mu sync.Mutex
+	events.RegisterHandler(func(ctx context.Context, event events.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, event)
+		return nil
+	})
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -20)
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, &createdAt)
+	// filling yesterday joins two streaks into a 7 day one
+	testDB.CreateTestStreak(t, ctx, running.ID, today.AddDate(0, 0, -6), today.AddDate(0, 0, -2))
+	testDB.CreateTestStreak(t, ctx, running.ID, today, today)
+
+	require.NoError(t, LogHabitForDate(ctx, "running", today.AddDate(0, 0, -1)))
+	require.Len(t, published, 2)
+	assert.Equal(t, events.HabitLogged, published[0].Type)
+	assert.Equal(t, events.StreakMilestone, published[1].Type)
+	assert.Equal(t, int64(7), published[1].Milestone)
+
+	published = published[:0]
+	require.NoError(t, UnlogHabitForDate(ctx, "running", today.AddDate(0, 0, -1)))
+	require.Len(t, published, 1)
+	assert.Equal(t, events.HabitUnlogged, published[0].Type)
+	assert.Equal(t, int64(7), published[0].PreviousStreak)
+	assert.Equal(t, int64(1), published[0].CurrentStreak)
+
+	published = published[:0]
+	require.NoError(t, LogHabitForDate(ctx, "smoking", today.AddDate(0, 0, -3)))
+	require.Len(t, published, 2)
+	assert.Equal(t, events.SlipupLogged, published[0].Type)
+	assert.Equal(t, events.StreakBroken, published[1].Type)
+	assert.Equal(t, int64(19), published[1].PreviousStreak)
+	assert.Equal(t, int64(2), published[1].CurrentStreak)
+
+	// unchanged days publish nothing
+	published = published[:0]
+	require.NoError(t, UnlogHabitForDate(ctx, "running", today.AddDate(0, 0, -1)))
+	assert.Empty(t, published)
+}
+
+// TestLogHabitsForToday_ConcurrentFileDB logs habits from many goroutines against a db file, each
+// transaction on its own connection like separate streakr processes, while read only connections
+// read along. None of them may fail with "database is locked" and no slip-up may get lost.
+func TestLogHabitsForToday_ConcurrentFileDB(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "streakr.db")
+	fileDB, err := store.OpenFileDBForTesting(path, false)
+	require.NoError(t, err)
+	testDB := &TestDB{DB: fileDB, Queries: generated.New(fileDB)}
+	store.SetDBForTesting(testDB.DB)
+	store.SetQueriesForTesting(testDB.Queries)
+	defer fileDB.Close()
+	createdAt := time.Now().AddDate(0, 0, -10)
+	running := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	smoking := testDB.CreateTestHabit(t, ctx, "smoking", "", store.HabitTypeQuit, &createdAt)
+
+	const workers, rounds = 8, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*workers)
+	for w := range workers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				if _, err := LogHabitsForTodayWithCount(ctx, []string{"running", "smoking"}, 1); err != nil {
+					errs <- fmt.Errorf("writer %d: %w", w, err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			readerDB, err := store.OpenFileDBForTesting(path, true)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer readerDB.Close()
+			q := generated.New(readerDB)
+			for range rounds {
+				if _, err := q.ListHabits(ctx); err != nil {
+					errs <- fmt.Errorf("reader %d: %w", w, err)
+					return
+				}
+				if _, err := q.ListSlipupsForHabit(ctx, smoking.ID); err != nil {
+					errs <- fmt.Errorf("reader %d: %w", w, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	slipups, err := testDB.Queries.ListSlipupsForHabit(ctx, smoking.ID)
+	require.NoError(t, err)
+	require.Len(t, slipups, 1)
+	assert.Equal(t, int64(workers*rounds), slipups[0].Count)
+	streaks, err := testDB.Queries.ListStreaksForHabit(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Len(t, streaks, 1)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+// strengthMultiplier is the per day decay used for habit strength, same as loop habit tracker
+// uses for a daily habit (0.5^(sqrt(1)/13)). A habit loses roughly half its strength
+// after 13 missed days.
+var strengthMultiplier = math.Pow(0.5, 1.0/13.0)
+
+// getTrackingStart returns the first day counted for a habit, quit habits start counting
+// clean days the day after creation unless there was a slip-up on the creation day itself.
+func getTrackingStart(habit generated.Habit, streaks []generated.Streak) time.Time {
+	if habit.HabitType != store.HabitTypeQuit {
+		return habit.CreatedAt
+	}
+	for _, streak := range streaks {
+		if util.IsSameDate(streak.StreakEnd, habit.CreatedAt) {
+			return habit.CreatedAt
+		}
+	}
+	return util.GetNextDayOf(habit.CreatedAt)
+}
+
+// getDailyCompletion returns one entry per day from getTrackingStart up to today.
+// for improve habits a day is complete if it falls inside a streak range.
+// for quit habits a day is complete if it is not a slip-up (streak_end) day.
+// today is only included when it already has a definite outcome (logged improve habit, or a slip-up).
+func getDailyCompletion(habit generated.Habit, streaks []generated.Streak, today time.Time) []bool {
+	start := getTrackingStart(habit, streaks)
+	if util.CompareDate(start, today) == -1 {
+		return []bool{}
+	}
+	days := make([]bool, util.GetDayDiff(start, today)+1)
+
+	if habit.HabitType == store.HabitTypeImprove {
+		for _, streak := range streaks {
+			for date := streak.StreakStart; util.CompareDate(date, streak.StreakEnd) >= 0; date = util.GetNextDayOf(date) {
+				if util.CompareDate(date, start) == 1 || util.CompareDate(date, today) == -1 {
+					continue
+				}
+				days[util.GetDayDiff(start, date)] = true
+			}
+		}
+		if !days[len(days)-1] {
+			// today isn't over yet, don't count it as a miss.
+			days = days[:len(days)-1]
+		}
+		return days
+	}
+
+	for i := range days {
+		days[i] = true
+	}
+	slipupToday := false
+	for _, streak := range streaks {
+		if util.CompareDate(streak.StreakEnd, start) == 1 {
+			continue
+		}
+		days[util.GetDayDiff(start, streak.StreakEnd)] = false
+		if util.IsSameDate(streak.StreakEnd, today) {
+			slipupToday = true
+		}
+	}
+	if !slipupToday {
+		days = days[:len(days)-1]
+	}
+	return days
+}
+
+// getStrengthSeries returns the habit strength (0-1) at the end of each day in dailyCompletion.
+func getStrengthSeries(dailyCompletion []bool) []float64 {
+	series := make([]float64, len(dailyCompletion))
+	score := 0.0
+	for i, done := range dailyCompletion {
+		checkmark := 0.0
+		if done {
+			checkmark = 1.0
+		}
+		score = score*strengthMultiplier + checkmark*(1-strengthMultiplier)
+		series[i] = score
+	}
+	return series
+}
+
+func getStrengthSeriesForHabit(appContext context.Context, habit generated.Habit) ([]float64, error) {
+	streaks, err := store.GetQueries().ListStreaksForHabit(appContext, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	return getStrengthSeries(getDailyCompletion(habit, streaks, time.Now())), nil
+}
+
+// GetHabitStrengthHistory returns the habit strength (0-100) for each of the past `days` days,
+// oldest first. Days before the habit was created are reported as 0.
+func GetHabitStrengthHistory(appContext context.Context, habit generated.Habit, days int) ([]float64, error) {
+	series, err := getStrengthSeriesForHabit(appContext, habit)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]float64, days)
+	offset := days - len(series)
+	for i := range history {
+		if i-offset < 0 {
+			continue
+		}
+		history[i] = series[i-offset] * 100
+	}
+	return history, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStrengthSeries(t *testing.T) {
+	tests := []struct {
+		name            string
+		dailyCompletion []bool
+		wantMin         float64
+		wantMax         float64
+	}{
+		{
+			name:            "no days tracked",
+			dailyCompletion: []bool{},
+		},
+		{
+			name:            "single completed day",
+			dailyCompletion: []bool{true},
+			wantMin:         0.05,
+			wantMax:         0.06,
+		},
+		{
+			name:            "long run of completed days approaches 1",
+			dailyCompletion: repeatBool(true, 200),
+			wantMin:         0.99,
+			wantMax:         1.0,
+		},
+		{
+			name:            "one miss after a long run barely dents strength",
+			dailyCompletion: append(repeatBool(true, 200), false),
+			wantMin:         0.94,
+			wantMax:         0.95,
+		},
+		{
+			name:            "never completed stays at 0",
+			dailyCompletion: repeatBool(false, 30),
+			wantMin:         0,
+			wantMax:         0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := getStrengthSeries(tt.dailyCompletion)
+			require.Len(t, series, len(tt.dailyCompletion))
+			if len(series) == 0 {
+				return
+			}
+			last := series[len(series)-1]
+			assert.GreaterOrEqual(t, last, tt.wantMin)
+			assert.LessOrEqual(t, last, tt.wantMax)
+		})
+	}
+}
+
+func TestGetDailyCompletion(t *testing.T) {
+	today := time.Date(2025, 11, 20, 12, 0, 0, 0, time.Local)
+
+	t.Run("improve habit skips unlogged today", func(t *testing.T) {
+		habit := generated.Habit{HabitType: store.HabitTypeImprove, CreatedAt: today.AddDate(0, 0, -4)}
+		streaks := []generated.Streak{
+			{StreakStart: today.AddDate(0, 0, -4), StreakEnd: today.AddDate(0, 0, -3)},
+			{StreakStart: today.AddDate(0, 0, -1), StreakEnd: today.AddDate(0, 0, -1)},
+		}
+		assert.Equal(t, []bool{true, true, false, true}, getDailyCompletion(habit, streaks, today))
+	})
+
+	t.Run("improve habit includes logged today", func(t *testing.T) {
+		habit := generated.Habit{HabitType: store.HabitTypeImprove, CreatedAt: today.AddDate(0, 0, -1)}
+		streaks := []generated.Streak{
+			{StreakStart: today.AddDate(0, 0, -1), StreakEnd: today},
+		}
+		assert.Equal(t, []bool{true, true}, getDailyCompletion(habit, streaks, today))
+	})
+
+	t.Run("quit habit marks slip-up days", func(t *testing.T) {
+		habit := generated.Habit{HabitType: store.HabitTypeQuit, CreatedAt: today.AddDate(0, 0, -5)}
+		streaks := []generated.Streak{
+			{StreakStart: today.AddDate(0, 0, -4), StreakEnd: today.AddDate(0, 0, -2)},
+		}
+		assert.Equal(t, []bool{true, true, false, true}, getDailyCompletion(habit, streaks, today))
+	})
+
+	t.Run("quit habit includes slip-up today", func(t *testing.T) {
+		habit := generated.Habit{HabitType: store.HabitTypeQuit, CreatedAt: today.AddDate(0, 0, -2)}
+		streaks := []generated.Streak{
+			{StreakStart: today.AddDate(0, 0, -1), StreakEnd: today},
+		}
+		assert.Equal(t, []bool{true, false}, getDailyCompletion(habit, streaks, today))
+	})
+}
+
+func TestGetHabitStrengthHistory(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -9)
+	habit := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestStreak(t, ctx, habit.ID, createdAt, today)
+
+	history, err := GetHabitStrengthHistory(ctx, habit, 30)
+	require.NoError(t, err)
+	require.Len(t, history, 30)
+
+	// days before creation have no strength
+	assert.Equal(t, 0.0, history[0])
+	// strength grows every consecutive day
+	for i := 21; i < 30; i++ {
+		assert.Greater(t, history[i], history[i-1])
+	}
+
+	stats, err := GetOverallStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.HabitInfos, 1)
+	assert.InDelta(t, history[29], stats.HabitInfos[0].Strength, 0.0001)
+}
+
+func repeatBool(val bool, n int) []bool {
+	res := make([]bool, n)
+	for i := range res {
+		res[i] = val
+	}
+	return res
+}
@@ -0,0 +1,513 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+const (
+	SyncOpAddHabit    = "habit.add"
+	SyncOpDeleteHabit = "habit.delete"
+	SyncOpEditHabit   = "habit.edit"
+	SyncOpLogDay      = "day.log"
+	SyncOpUnlogDay    = "day.unlog"
+)
+
+const (
+	syncDayLayout = "2006-01-02"
+	syncLogExt    = ".jsonl"
+)
+
+// syncOpKey groups the ops that overwrite each other, for every key the latest op wins.
+func syncOpKey(op types.SyncOp) string {
+	switch op.Op {
+	case SyncOpEditHabit:
+		return "costs/" + op.Habit
+	case SyncOpLogDay, SyncOpUnlogDay:
+		return "day/" + op.Habit + "/" + op.Day
+	default:
+		return "habit/" + op.Habit
+	}
+}
+
+// isSyncOpAfter orders ops by time, then device and seq, so every device picks the same winner for a key.
+func isSyncOpAfter(a, b types.SyncOp) bool {
+	if !a.Time.Equal(b.Time) {
+		return a.Time.After(b.Time)
+	}
+	if a.Device != b.Device {
+		return a.Device > b.Device
+	}
+	return a.Seq > b.Seq
+}
+
+// habitSyncOp is dated at the creation of habit, so it sorts before any change to it even when
+// it is recorded later by a snapshot.
+func habitSyncOp(habit generated.Habit) types.SyncOp {
+	return types.SyncOp{
+		Time:        habit.CreatedAt,
+		Op:          SyncOpAddHabit,
+		Habit:       habit.Slug,
+		Name:        habit.Name,
+		Description: habit.Description.String,
+		HabitType:   habit.HabitType,
+		CreatedAt:   habit.CreatedAt,
+	}
+}
+
+func habitCostsSyncOp(habit generated.Habit) types.SyncOp {
+	return types.SyncOp{
+		Op:                   SyncOpEditHabit,
+		Habit:                habit.Slug,
+		CostPerOccurrence:    habit.CostPerOccurrence.Float64,
+		MinutesPerOccurrence: habit.MinutesPerOccurrence.Int64,
+	}
+}
+
+// daySyncOp records whether habit was logged on date, count is the number of slip-ups of a quit habit that day.
+func daySyncOp(habit generated.Habit, date time.Time, logged bool, count int64) types.SyncOp {
+	op := types.SyncOp{
+		Op:    SyncOpUnlogDay,
+		Habit: habit.Slug,
+		Day:   date.Format(syncDayLayout),
+	}
+	if logged {
+		op.Op = SyncOpLogDay
+		if habit.HabitType == store.HabitTypeQuit {
+			op.Count = count
+		}
+	}
+	return op
+}
+
+// recordSyncOp adds a change made on this device to its sync log, the next `streakr sync` shares it.
+func recordSyncOp(appContext context.Context, q *generated.Queries, op types.SyncOp) error {
+	if op.Time.IsZero() {
+		op.Time = time.Now()
+	}
+	payload, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	_, err = q.AddLocalSyncOp(appContext, generated.AddLocalSyncOpParams{
+		Key:     syncOpKey(op),
+		OpTime:  op.Time.UnixNano(),
+		Payload: string(payload),
+	})
+	return err
+}
+
+// recordSyncSnapshot records the current state of every habit as ops, so the first sync of a device
+// also shares what was tracked before ops were recorded. Keys that already have an op are up to date.
+func recordSyncSnapshot(appContext context.Context, q *generated.Queries) error {
+	recordIfMissing := func(op types.SyncOp) error {
+		_, err := q.GetLatestSyncOpForKey(appContext, syncOpKey(op))
+		if err == nil || !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		return recordSyncOp(appContext, q, op)
+	}
+	habits, err := q.ListHabits(appContext)
+	if err != nil {
+		return err
+	}
+	for _, habit := range habits {
+		if err = recordIfMissing(habitSyncOp(habit)); err != nil {
+			return err
+		}
+		if habit.CostPerOccurrence.Valid || habit.MinutesPerOccurrence.Valid {
+			if err = recordIfMissing(habitCostsSyncOp(habit)); err != nil {
+				return err
+			}
+		}
+		streaks, err := q.ListStreaksForHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		slipups, err := q.ListSlipupsForHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		slipupCounts := make(map[string]int64)
+		for _, slipup := range slipups {
+			slipupCounts[slipup.SlipDate.Format(syncDayLayout)] = slipup.Count
+		}
+		for _, day := range store.LoggedDays(habit, streaks) {
+			count := max(slipupCounts[day.Format(syncDayLayout)], 1)
+			if err = recordIfMissing(daySyncOp(habit, day, true, count)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getSlipupCountOnDate returns the slip-ups of a quit habit on date, a logged day without a count is a single slip-up.
+func getSlipupCountOnDate(appContext context.Context, q *generated.Queries, habit generated.Habit, date time.Time) (int64, error) {
+	slipups, err := q.ListSlipupsForHabit(appContext, habit.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, slipup := range slipups {
+		if util.IsSameDate(slipup.SlipDate, date) {
+			return slipup.Count, nil
+		}
+	}
+	return 1, nil
+}
+
+// LoadOrCreateSyncDevice returns the name this device writes its sync log under, creating one on first use.
+func LoadOrCreateSyncDevice(devicePath string) (string, error) {
+	data, err := os.ReadFile(devicePath)
+	if err == nil {
+		device := strings.TrimSpace(string(data))
+		if device != "" {
+			return device, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	buf := make([]byte, 3)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	device := hex.EncodeToString(buf)
+	if hostname, err := os.Hostname(); err == nil && util.Slugify(hostname) != "" {
+		device = util.Slugify(hostname) + "-" + device
+	}
+	if err = os.WriteFile(devicePath, []byte(device+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return device, nil
+}
+
+// Sync exchanges changes with other devices through a shared folder holding one append-only log per device.
+// The ops of other devices are replayed here, keeping the latest op per habit and per habit-day,
+// then the ops made on this device are appended to its own log. dryRun reports the incoming changes only.
+func Sync(appContext context.Context, folder, device string, dryRun bool) (*types.SyncResult, error) {
+	info, err := os.Stat(folder)
+	if err != nil || !info.IsDir() {
+		return nil, &se.StreakrError{TerminalMsg: fmt.Sprintf("sync folder %s does not exist", folder)}
+	}
+	logPath := filepath.Join(folder, device+syncLogExt)
+	result := &types.SyncResult{LogPath: logPath}
+
+	ownLog, err := os.ReadFile(logPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	firstSync := errors.Is(err, os.ErrNotExist)
+	var exportedSeq int64
+	ownOps, err := parseSyncLog(logPath, ownLog)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ownOps {
+		exportedSeq = max(exportedSeq, op.Seq)
+	}
+	incoming, err := readIncomingSyncOps(appContext, folder, device)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	if firstSync {
+		if err = recordSyncSnapshot(appContext, qtx); err != nil {
+			return nil, err
+		}
+	}
+	for _, op := range incoming {
+		applied, err := applySyncOp(appContext, qtx, device, op)
+		if err != nil {
+			return nil, err
+		}
+		if applied {
+			result.Applied = append(result.Applied, op)
+		} else {
+			result.Overridden++
+		}
+	}
+	outgoing, err := qtx.ListLocalSyncOpsAfter(appContext, exportedSeq)
+	if err != nil {
+		return nil, err
+	}
+	result.Outgoing = len(outgoing)
+	if dryRun {
+		return result, nil
+	}
+	// commit before writing the log, a failed write is simply retried by the next sync.
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	unfinishedLine := len(ownLog) > 0 && ownLog[len(ownLog)-1] != '\n'
+	if err = appendSyncLog(logPath, device, outgoing, unfinishedLine); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readIncomingSyncOps returns the ops of other devices that were not replayed yet, oldest first.
+func readIncomingSyncOps(appContext context.Context, folder, device string) ([]types.SyncOp, error) {
+	paths, err := filepath.Glob(filepath.Join(folder, "*"+syncLogExt))
+	if err != nil {
+		return nil, err
+	}
+	incoming := make([]types.SyncOp, 0)
+	for _, path := range paths {
+		logDevice := strings.TrimSuffix(filepath.Base(path), syncLogExt)
+		if logDevice == device {
+			continue
+		}
+		replayedSeq, err := store.GetQueries().GetMaxSyncSeqForDevice(appContext, logDevice)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		ops, err := parseSyncLog(path, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range ops {
+			if op.Seq <= replayedSeq {
+				continue
+			}
+			replayedSeq = op.Seq
+			op.Device = logDevice
+			incoming = append(incoming, op)
+		}
+	}
+	sort.Slice(incoming, func(i, j int) bool {
+		return isSyncOpAfter(incoming[j], incoming[i])
+	})
+	return incoming, nil
+}
+
+// parseSyncLog parses the json lines of a device log, lines of an encrypted database are sealed and base64 encoded.
+// An unfinished last line (e.g. still being copied by the sync tool) is left for the next run, malformed lines
+// are skipped. Sealed lines that can't be opened fail the sync, skipping them would lose their ops for good.
+func parseSyncLog(path string, data []byte) ([]types.SyncOp, error) {
+	lines := bytes.Split(data, []byte("\n"))
+	ops := make([]types.SyncOp, 0, len(lines))
+	for i, line := range lines[:len(lines)-1] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '{' {
+			var err error
+			if line, err = openSyncLine(line); err != nil {
+				return nil, &se.StreakrError{
+					TerminalMsg: fmt.Sprintf("could not read %s line %d, it is encrypted: every device has to use the same passphrase", path, i+1),
+					Err:         err,
+				}
+			}
+		}
+		var op types.SyncOp
+		if err := json.Unmarshal(line, &op); err != nil {
+			slog.Warn("skipping malformed sync op", "path", path, "line", i+1, "err", err.Error())
+			continue
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// sealSyncLine keeps an op of an encrypted database encrypted in the shared folder.
+func sealSyncLine(line []byte) ([]byte, error) {
+	if !store.IsEncrypted() {
+		return line, nil
+	}
+	sealed, err := store.SealRecord(line)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+func openSyncLine(line []byte) ([]byte, error) {
+	if !store.IsEncrypted() {
+		return nil, errors.New("this database is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(line))
+	if err != nil {
+		return nil, err
+	}
+	return store.OpenRecord(sealed)
+}
+
+func appendSyncLog(logPath, device string, ops []generated.SyncOp, unfinishedLine bool) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if unfinishedLine {
+		// end the line an interrupted write left behind, it is skipped as malformed
+		buf.WriteByte('\n')
+	}
+	for _, row := range ops {
+		op, err := syncOpFromRow(row, device)
+		if err != nil {
+			return err
+		}
+		line, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+		if line, err = sealSyncLine(line); err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncOpFromRow restores a stored op, ops made on this device are stored without a device.
+func syncOpFromRow(row generated.SyncOp, localDevice string) (types.SyncOp, error) {
+	var op types.SyncOp
+	if err := json.Unmarshal([]byte(row.Payload), &op); err != nil {
+		return op, err
+	}
+	op.Device = row.Device
+	if op.Device == "" {
+		op.Device = localDevice
+	}
+	op.Seq = row.Seq
+	return op, nil
+}
+
+// applySyncOp stores an op of another device and applies it unless a later op for the same key is known.
+func applySyncOp(appContext context.Context, q *generated.Queries, localDevice string, op types.SyncOp) (bool, error) {
+	key := syncOpKey(op)
+	newer := true
+	latestRow, err := q.GetLatestSyncOpForKey(appContext, key)
+	if err == nil {
+		latest, err := syncOpFromRow(latestRow, localDevice)
+		if err != nil {
+			return false, err
+		}
+		newer = isSyncOpAfter(op, latest)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	payload, err := json.Marshal(op)
+	if err != nil {
+		return false, err
+	}
+	err = q.AddSyncOp(appContext, generated.AddSyncOpParams{
+		Device:  op.Device,
+		Seq:     op.Seq,
+		Key:     key,
+		OpTime:  op.Time.UnixNano(),
+		Payload: string(payload),
+	})
+	if err != nil || !newer {
+		return false, err
+	}
+	return true, replaySyncOp(appContext, q, op)
+}
+
+// replaySyncOp makes the change of an op without recording it again. Ops for habits that don't exist here
+// (e.g. deleted later on another device) are no-ops.
+func replaySyncOp(appContext context.Context, q *generated.Queries, op types.SyncOp) error {
+	habit, err := q.GetHabitBySlug(appContext, op.Habit)
+	exists := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	switch op.Op {
+	case SyncOpAddHabit:
+		if exists {
+			return nil
+		}
+		_, err = q.AddHabitWithCreatedAt(appContext, generated.AddHabitWithCreatedAtParams{
+			Name:        op.Name,
+			Slug:        op.Habit,
+			Description: sql.NullString{String: op.Description, Valid: op.Description != ""},
+			HabitType:   op.HabitType,
+			CreatedAt:   op.CreatedAt,
+		})
+		return err
+	case SyncOpDeleteHabit:
+		if !exists {
+			return nil
+		}
+		return q.DeleteHabit(appContext, habit.ID)
+	case SyncOpEditHabit:
+		if !exists {
+			return nil
+		}
+		return q.UpdateHabitCosts(appContext, generated.UpdateHabitCostsParams{
+			ID:                   habit.ID,
+			CostPerOccurrence:    sql.NullFloat64{Float64: op.CostPerOccurrence, Valid: op.CostPerOccurrence > 0},
+			MinutesPerOccurrence: sql.NullInt64{Int64: op.MinutesPerOccurrence, Valid: op.MinutesPerOccurrence > 0},
+		})
+	case SyncOpLogDay, SyncOpUnlogDay:
+		if !exists {
+			return nil
+		}
+		date, err := time.ParseInLocation(syncDayLayout, op.Day, time.Local)
+		if err != nil {
+			slog.Warn("skipping sync op with invalid day", "device", op.Device, "seq", op.Seq, "day", op.Day)
+			return nil
+		}
+		if util.CompareDate(habit.CreatedAt, date) == -1 || util.CompareDate(date, time.Now()) == -1 {
+			slog.Warn("skipping sync op outside the tracked days of the habit", "device", op.Device, "seq", op.Seq, "habit", op.Habit, "day", op.Day)
+			return nil
+		}
+		date = util.GetNoonOf(date)
+		logged := op.Op == SyncOpLogDay
+		if _, err = writeHabitLoggedOnDate(appContext, q, habit, date, logged); err != nil {
+			return err
+		}
+		if habit.HabitType != store.HabitTypeQuit {
+			return nil
+		}
+		if err = q.DeleteSlipup(appContext, generated.DeleteSlipupParams{HabitID: habit.ID, SlipDate: date}); err != nil {
+			return err
+		}
+		if !logged {
+			return nil
+		}
+		return q.AddSlipupCount(appContext, generated.AddSlipupCountParams{HabitID: habit.ID, SlipDate: date, Count: max(op.Count, 1)})
+	default:
+		slog.Warn("skipping unknown sync op", "device", op.Device, "seq", op.Seq, "op", op.Op)
+		return nil
+	}
+}
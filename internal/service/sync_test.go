@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useTestDB(testDB *TestDB) {
+	store.SetDBForTesting(testDB.DB)
+	store.SetQueriesForTesting(testDB.Queries)
+}
+
+func isLoggedOn(t *testing.T, ctx context.Context, habitName string, date time.Time) bool {
+	t.Helper()
+	habit, err := GetHabitByName(ctx, habitName)
+	require.NoError(t, err)
+	streaks, err := store.GetQueries().ListStreaksForHabit(ctx, habit.ID)
+	require.NoError(t, err)
+	for _, day := range store.LoggedDays(habit, streaks) {
+		if util.IsSameDate(day, date) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSync_BetweenDevices(t *testing.T) {
+	ctx := context.Background()
+	folder := t.TempDir()
+	now := time.Now()
+	createdAt := now.AddDate(0, 0, -10)
+	threeDaysAgo := now.AddDate(0, 0, -3)
+
+	laptop := SetupTestDB(t)
+	defer laptop.Cleanup()
+	// created without a sync op, the first sync shares it through a snapshot
+	laptop.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	require.NoError(t, LogHabitForDate(ctx, "running", threeDaysAgo))
+	result, err := Sync(ctx, folder, "laptop", false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, filepath.Join(folder, "laptop.jsonl"), result.LogPath)
+	assert.Greater(t, result.Outgoing, 0)
+
+	desktop := SetupTestDB(t)
+	defer desktop.Cleanup()
+	require.NoError(t, AddHabit(ctx, "reading", "", store.HabitTypeImprove))
+
+	result, err = Sync(ctx, folder, "desktop", true)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Applied)
+	_, err = GetHabitByName(ctx, "running")
+	assert.Error(t, err, "dry run should not change the db")
+	_, err = os.Stat(filepath.Join(folder, "desktop.jsonl"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "dry run should not write the log")
+
+	_, err = Sync(ctx, folder, "desktop", false)
+	require.NoError(t, err)
+	running, err := GetHabitByName(ctx, "running")
+	require.NoError(t, err)
+	assert.True(t, util.IsSameDate(createdAt, running.CreatedAt))
+	assert.True(t, isLoggedOn(t, ctx, "running", threeDaysAgo))
+
+	require.NoError(t, UnlogHabitForDate(ctx, "running", threeDaysAgo))
+	_, err = Sync(ctx, folder, "desktop", false)
+	require.NoError(t, err)
+
+	useTestDB(laptop)
+	_, err = Sync(ctx, folder, "laptop", false)
+	require.NoError(t, err)
+	_, err = GetHabitByName(ctx, "reading")
+	assert.NoError(t, err)
+	assert.False(t, isLoggedOn(t, ctx, "running", threeDaysAgo))
+
+	// nothing new on either side
+	result, err = Sync(ctx, folder, "laptop", false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, 0, result.Outgoing)
+}
+
+func TestSync_LastWriterWins(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+	ctx := context.Background()
+	folder := t.TempDir()
+	now := time.Now()
+	createdAt := now.AddDate(0, 0, -10)
+	yesterday := now.AddDate(0, 0, -1)
+
+	habit := testDB.CreateTestHabit(t, ctx, "running", "", store.HabitTypeImprove, &createdAt)
+	require.NoError(t, LogHabitForDate(ctx, "running", yesterday))
+
+	writeOps := func(ops ...types.SyncOp) {
+		file, err := os.OpenFile(filepath.Join(folder, "phone.jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		defer file.Close()
+		for _, op := range ops {
+			line, err := json.Marshal(op)
+			require.NoError(t, err)
+			_, err = file.Write(append(line, '\n'))
+			require.NoError(t, err)
+		}
+	}
+	unlog := daySyncOp(habit, yesterday, false, 0)
+
+	// an unlog made before the local log loses
+	unlog.Seq, unlog.Time = 1, now.Add(-time.Hour)
+	writeOps(unlog)
+	result, err := Sync(ctx, folder, "laptop", false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, 1, result.Overridden)
+	assert.True(t, isLoggedOn(t, ctx, "running", yesterday))
+
+	// a later one wins, a line still being written is left for the next sync
+	unlog.Seq, unlog.Time = 2, now.Add(time.Hour)
+	writeOps(unlog)
+	file, err := os.OpenFile(filepath.Join(folder, "phone.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"device":"phone","seq":3,`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	result, err = Sync(ctx, folder, "laptop", false)
+	require.NoError(t, err)
+	require.Len(t, result.Applied, 1)
+	assert.Equal(t, SyncOpUnlogDay, result.Applied[0].Op)
+	assert.False(t, isLoggedOn(t, ctx, "running", yesterday))
+}
+
+func TestSync_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	folder := t.TempDir()
+	createdAt := time.Now().AddDate(0, 0, -10)
+	t.Cleanup(func() { store.SetEncryptionForTesting(nil) })
+
+	laptop := SetupTestDB(t)
+	defer laptop.Cleanup()
+	require.NoError(t, store.SetEncryptionForTesting([]byte("hunter2")))
+	laptop.CreateTestHabit(t, ctx, "therapy", "", store.HabitTypeImprove, &createdAt)
+	_, err := Sync(ctx, folder, "laptop", false)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(folder, "laptop.jsonl"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "therapy")
+
+	// another device with the same passphrase, but a key of its own
+	desktop := SetupTestDB(t)
+	defer desktop.Cleanup()
+	require.NoError(t, store.SetEncryptionForTesting([]byte("hunter2")))
+	_, err = Sync(ctx, folder, "desktop", false)
+	require.NoError(t, err)
+	_, err = GetHabitByName(ctx, "therapy")
+	assert.NoError(t, err)
+
+	// a wrong passphrase or a plain database can't read the log, and must not skip it
+	phone := SetupTestDB(t)
+	defer phone.Cleanup()
+	require.NoError(t, store.SetEncryptionForTesting([]byte("wrong")))
+	_, err = Sync(ctx, folder, "phone", false)
+	assert.Error(t, err)
+	require.NoError(t, store.SetEncryptionForTesting(nil))
+	_, err = Sync(ctx, folder, "phone", false)
+	assert.Error(t, err)
+}
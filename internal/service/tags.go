@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+)
+
+// NormalizeTags lowercases, trims and validates tag names, dropping duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, &se.StreakrError{TerminalMsg: "tag name cannot be empty"}
+		}
+		if len(tag) > 20 {
+			return nil, &se.StreakrError{TerminalMsg: "tag name cannot exceed 20 characters"}
+		}
+		if strings.ContainsAny(tag, " \t,") {
+			return nil, &se.StreakrError{TerminalMsg: fmt.Sprintf("tag name '%s' should be a single word", tag)}
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+func TagHabit(appContext context.Context, habitName string, tags []string) error {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return err
+	}
+	tags, err = NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	for _, tag := range tags {
+		tagID, err := qtx.UpsertTag(appContext, tag)
+		if err != nil {
+			return err
+		}
+		err = qtx.AddHabitTag(appContext, generated.AddHabitTagParams{
+			HabitID: habit.ID,
+			TagID:   tagID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func UntagHabit(appContext context.Context, habitName string, tags []string) error {
+	habit, err := GetHabitByName(appContext, habitName)
+	if err != nil {
+		return err
+	}
+	tags, err = NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	tx, err := store.GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := store.GetQueries().WithTx(tx)
+	for _, tag := range tags {
+		err = qtx.RemoveHabitTag(appContext, generated.RemoveHabitTagParams{
+			HabitID: habit.ID,
+			Name:    tag,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err = qtx.DeleteUnusedTags(appContext); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func ListTags(appContext context.Context) ([]string, error) {
+	return store.GetQueries().ListTags(appContext)
+}
+
+func GetTagsForHabit(appContext context.Context, habit generated.Habit) ([]string, error) {
+	return store.GetQueries().ListTagsForHabit(appContext, habit.ID)
+}
+
+// ListHabitsByTag returns habits carrying the tag, an unknown tag is an error so typos don't go unnoticed.
+func ListHabitsByTag(appContext context.Context, tag string) ([]generated.Habit, error) {
+	habits, err := store.GetQueries().ListHabitsByTag(appContext, strings.ToLower(tag))
+	if err != nil {
+		return nil, err
+	}
+	if len(habits) == 0 {
+		return nil, &se.StreakrError{TerminalMsg: fmt.Sprintf("No habits with tag %s", tag), Kind: se.KindNotFound}
+	}
+	return habits, nil
+}
+
+// ListImproveHabitsByTag returns the improve habits carrying the tag. Quit habits are left out as
+// logging them records a slip-up, they have to be logged by name.
+func ListImproveHabitsByTag(appContext context.Context, tag string) ([]generated.Habit, error) {
+	habits, err := ListHabitsByTag(appContext, tag)
+	if err != nil {
+		return nil, err
+	}
+	improveHabits := make([]generated.Habit, 0, len(habits))
+	for _, habit := range habits {
+		if habit.HabitType == store.HabitTypeImprove {
+			improveHabits = append(improveHabits, habit)
+		}
+	}
+	if len(improveHabits) == 0 {
+		return nil, &se.StreakrError{
+			TerminalMsg: fmt.Sprintf("No improve habits with tag %s, quit habits have to be logged by name", tag),
+			Kind:        se.KindNotFound,
+		}
+	}
+	return improveHabits, nil
+}
+
+func GetTodaysLoggedHabitCountByTag(appContext context.Context, tag string) (int64, int64, error) {
+	tag = strings.ToLower(tag)
+	totalImprovementHabits, err := store.GetQueries().CountTotalImproveHabitsByTag(appContext, tag)
+	if err != nil {
+		return 0, 0, err
+	}
+	completedImprovementHabits, err := store.GetQueries().CountImproveHabitsLoggedTodayByTag(appContext, tag)
+	if err != nil {
+		return 0, 0, err
+	}
+	return completedImprovementHabits, totalImprovementHabits, nil
+}
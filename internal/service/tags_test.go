@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Health", "morning", "health"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"health", "morning"}, tags)
+
+	_, err = NormalizeTags([]string{""})
+	assert.Error(t, err)
+	_, err = NormalizeTags([]string{"two words"})
+	assert.Error(t, err)
+}
+
+func TestTagAndUntagHabit(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, nil)
+
+	require.NoError(t, TagHabit(ctx, "running", []string{"health", "morning"}))
+	require.NoError(t, TagHabit(ctx, "reading", []string{"morning"}))
+	// tagging twice is a no-op
+	require.NoError(t, TagHabit(ctx, "running", []string{"health"}))
+
+	tags, err := GetTagsForHabit(ctx, running)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"health", "morning"}, tags)
+
+	habits, err := ListHabitsByTag(ctx, "morning")
+	require.NoError(t, err)
+	assert.Len(t, habits, 2)
+
+	require.NoError(t, UntagHabit(ctx, "running", []string{"health"}))
+	allTags, err := ListTags(ctx)
+	require.NoError(t, err)
+	// unused tags are dropped
+	assert.Equal(t, []string{"morning"}, allTags)
+
+	_, err = ListHabitsByTag(ctx, "health")
+	assert.Error(t, err)
+	assert.Error(t, TagHabit(ctx, "nonexistent", []string{"health"}))
+}
+
+func TestTagCountsAndSubtotals(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	today := time.Now()
+	createdAt := today.AddDate(0, 0, -10)
+	running := testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "reading", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestHabit(t, ctx, "coding", "test", store.HabitTypeImprove, &createdAt)
+	testDB.CreateTestStreak(t, ctx, running.ID, today.AddDate(0, 0, -2), today)
+
+	require.NoError(t, TagHabit(ctx, "running", []string{"health"}))
+	require.NoError(t, TagHabit(ctx, "reading", []string{"health"}))
+
+	logged, total, err := GetTodaysLoggedHabitCountByTag(ctx, "health")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), logged)
+	assert.Equal(t, int64(2), total)
+
+	overallStats, err := GetOverallStatsByTag(ctx, "health")
+	require.NoError(t, err)
+	assert.Len(t, overallStats.HabitInfos, 2)
+	require.Len(t, overallStats.TagSubtotals, 1)
+	assert.Equal(t, "health", overallStats.TagSubtotals[0].Tag)
+	assert.Equal(t, 2, overallStats.TagSubtotals[0].HabitCount)
+	assert.Equal(t, int64(3), overallStats.TagSubtotals[0].TotalPerformedDays)
+}
+
+func TestListImproveHabitsByTag(t *testing.T) {
+	testDB := SetupTestDB(t)
+	defer testDB.Cleanup()
+
+	ctx := context.Background()
+	testDB.CreateTestHabit(t, ctx, "running", "test", store.HabitTypeImprove, nil)
+	testDB.CreateTestHabit(t, ctx, "smoking", "test", store.HabitTypeQuit, nil)
+	require.NoError(t, TagHabit(ctx, "running", []string{"evening"}))
+	require.NoError(t, TagHabit(ctx, "smoking", []string{"evening", "vices"}))
+
+	habits, err := ListImproveHabitsByTag(ctx, "evening")
+	require.NoError(t, err)
+	require.Len(t, habits, 1)
+	assert.Equal(t, "running", habits[0].Name)
+
+	// logging the tag must not record a slip-up
+	_, err = LogHabitsForTodayWithCount(ctx, []string{habits[0].Slug}, 3)
+	require.NoError(t, err)
+	logged, total, err := GetTodaysLoggedHabitCountByTag(ctx, "evening")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), logged)
+	assert.Equal(t, int64(1), total)
+	smoking, err := GetHabitByName(ctx, "smoking")
+	require.NoError(t, err)
+	slipups, err := store.GetQueries().ListSlipupsForHabit(ctx, smoking.ID)
+	require.NoError(t, err)
+	assert.Empty(t, slipups)
+
+	// a tag of quit habits only has nothing to log
+	_, err = ListImproveHabitsByTag(ctx, "vices")
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Atharva21/streakr/internal/store"
 	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/util"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 )
@@ -35,9 +36,13 @@ func SetupTestDB(t *testing.T) *TestDB {
 		name TEXT NOT NULL UNIQUE CHECK (length(name) <= 20),
 		description TEXT CHECK (description IS NULL OR length(description) <= 200),
 		habit_type TEXT CHECK (habit_type IN ('improve', 'quit')) NOT NULL DEFAULT 'improve',
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		cost_per_occurrence REAL CHECK (cost_per_occurrence IS NULL OR cost_per_occurrence >= 0),
+		minutes_per_occurrence INTEGER CHECK (minutes_per_occurrence IS NULL OR minutes_per_occurrence >= 0),
+		slug TEXT NOT NULL DEFAULT ''
 	);
 	CREATE INDEX idx_habits_name ON habits(name);
+	CREATE UNIQUE INDEX idx_habits_slug ON habits(slug);
 
 	CREATE TABLE streaks (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -47,6 +52,82 @@ func SetupTestDB(t *testing.T) *TestDB {
 		FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
 	);
 	CREATE INDEX idx_streaks_habit_id ON streaks(habit_id);
+
+	CREATE TABLE habit_reminders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		habit_id INTEGER NOT NULL,
+		remind_at TEXT NOT NULL CHECK (remind_at GLOB '[0-2][0-9]:[0-5][0-9]'),
+		FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE,
+		UNIQUE (habit_id, remind_at)
+	);
+	CREATE INDEX idx_habit_reminders_habit_id ON habit_reminders(habit_id);
+
+	CREATE TABLE achievements (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		code TEXT NOT NULL,
+		habit_id INTEGER,
+		unlocked_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+	);
+	CREATE UNIQUE INDEX idx_achievements_code_habit_id ON achievements(code, COALESCE(habit_id, 0));
+
+	CREATE TABLE tags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE CHECK (length(name) <= 20)
+	);
+
+	CREATE TABLE habit_tags (
+		habit_id INTEGER NOT NULL,
+		tag_id INTEGER NOT NULL,
+		PRIMARY KEY (habit_id, tag_id),
+		FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE,
+		FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+	);
+	CREATE INDEX idx_habit_tags_tag_id ON habit_tags(tag_id);
+
+	CREATE TABLE routines (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE CHECK (length(name) <= 20),
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE routine_habits (
+		routine_id INTEGER NOT NULL,
+		habit_name TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		PRIMARY KEY (routine_id, habit_name),
+		FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE goals (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		habit_id INTEGER NOT NULL,
+		kind TEXT CHECK (kind IN ('total_days', 'streak', 'rate')) NOT NULL,
+		target INTEGER NOT NULL CHECK (target > 0),
+		start_date DATE NOT NULL,
+		deadline DATE NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+	);
+	CREATE INDEX idx_goals_habit_id ON goals(habit_id);
+
+	CREATE TABLE slipups (
+		habit_id INTEGER NOT NULL,
+		slip_date DATE NOT NULL,
+		count INTEGER NOT NULL CHECK (count > 0),
+		PRIMARY KEY (habit_id, slip_date),
+		FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE sync_ops (
+		device TEXT NOT NULL,
+		seq INTEGER NOT NULL,
+		key TEXT NOT NULL,
+		op_time INTEGER NOT NULL,
+		payload TEXT NOT NULL,
+		PRIMARY KEY (device, seq)
+	);
+	CREATE INDEX idx_sync_ops_key ON sync_ops(key);
 	`
 
 	_, err = db.Exec(schema)
@@ -76,6 +157,7 @@ func (tdb *TestDB) CreateTestHabit(t *testing.T, ctx context.Context, name, desc
 
 	id, err := tdb.Queries.AddHabit(ctx, generated.AddHabitParams{
 		Name: name,
+		Slug: util.Slugify(name),
 		Description: sql.NullString{
 			String: description,
 			Valid:  description != "",
@@ -90,7 +172,7 @@ func (tdb *TestDB) CreateTestHabit(t *testing.T, ctx context.Context, name, desc
 		require.NoError(t, err, "Failed to update created_at")
 	}
 
-	habit, err := tdb.Queries.GetHabitByName(ctx, name)
+	habit, err := tdb.Queries.GetHabitBySlug(ctx, util.Slugify(name))
 	require.NoError(t, err, "Failed to get created habit")
 
 	return habit
@@ -0,0 +1,257 @@
+package store
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+const (
+	// EncryptedSuffix is appended to the db path for the encrypted snapshot.
+	EncryptedSuffix = ".enc"
+	// BackupSuffix is appended to the encrypted snapshot path for the previous snapshot.
+	BackupSuffix = ".bak"
+)
+
+// PassphraseFunc supplies the passphrase of an encrypted db, it is only called when one exists.
+type PassphraseFunc func() ([]byte, error)
+
+var (
+	// encryptionKey is set while the db is encrypted, snapshotHash is the hash of the last saved snapshot.
+	encryptionKey *snapshotKey
+	snapshotHash  [sha256.Size]byte
+	// sealedHash is the hash of the snapshot file as it was read or last written, to notice it being
+	// replaced behind our back.
+	sealedHash [sha256.Size]byte
+	// encryptionPassphrase opens records sealed by other devices, their keys are derived once by header.
+	encryptionPassphrase []byte
+	recordKeys           = make(map[string]*snapshotKey)
+)
+
+func IsEncrypted() bool {
+	return encryptionKey != nil
+}
+
+// bootstrapEncryptedStore decrypts the snapshot into an in memory db, it is written back by Flush.
+func bootstrapEncryptedStore(passphrase PassphraseFunc) error {
+	if err := acquireStoreLock(); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(storePath + EncryptedSuffix)
+	if err != nil {
+		return err
+	}
+	pass, err := passphrase()
+	if err != nil {
+		return err
+	}
+	plaintext, key, err := openSnapshot(data, pass)
+	if err != nil {
+		return fmt.Errorf("could not decrypt %s: %w", storePath+EncryptedSuffix, err)
+	}
+
+	if err = openMemoryDB(plaintext); err != nil {
+		return err
+	}
+	if err = protectReadOnly(); err != nil {
+		return err
+	}
+	encryptionKey = key
+	encryptionPassphrase = pass
+	recordKeys[string(key.header())] = key
+	snapshotHash = sha256.Sum256(plaintext)
+	sealedHash = sha256.Sum256(data)
+	return nil
+}
+
+// reloadEncrypted decrypts the snapshot into a new in memory db if it was replaced since it was read.
+func reloadEncrypted() error {
+	data, err := os.ReadFile(storePath + EncryptedSuffix)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(data) == sealedHash {
+		return nil
+	}
+	key, err := keyForHeader(data)
+	if err != nil {
+		return err
+	}
+	plaintext, err := openSnapshotWithKey(data, key)
+	if err != nil {
+		return fmt.Errorf("could not decrypt %s: %w", storePath+EncryptedSuffix, err)
+	}
+	if err = reopenMemoryDB(plaintext, nil); err != nil {
+		return err
+	}
+	encryptionKey = key
+	snapshotHash = sha256.Sum256(plaintext)
+	sealedHash = sha256.Sum256(data)
+	return nil
+}
+
+// SealRecord encrypts data that leaves the db, e.g. sync logs, the same way as the db itself.
+func SealRecord(plaintext []byte) ([]byte, error) {
+	if encryptionKey == nil {
+		return nil, errors.New("database is not encrypted")
+	}
+	return sealSnapshot(plaintext, encryptionKey)
+}
+
+// OpenRecord decrypts a record sealed by SealRecord here or on another device with the same passphrase.
+func OpenRecord(sealed []byte) ([]byte, error) {
+	if encryptionKey == nil {
+		return nil, errors.New("database is not encrypted")
+	}
+	key, err := keyForHeader(sealed)
+	if err != nil {
+		return nil, err
+	}
+	return openSnapshotWithKey(sealed, key)
+}
+
+// keyForHeader returns the key for the header of sealed, it is derived from the passphrase once per header.
+func keyForHeader(sealed []byte) (*snapshotKey, error) {
+	params, salt, err := parseSnapshotHeader(sealed)
+	if err != nil {
+		return nil, err
+	}
+	header := string(sealed[:snapshotHeaderSize])
+	key, ok := recordKeys[header]
+	if !ok {
+		if key, err = deriveSnapshotKey(encryptionPassphrase, salt, params); err != nil {
+			return nil, err
+		}
+		recordKeys[header] = key
+	}
+	return key, nil
+}
+
+// flushEncrypted writes the in memory db to the encrypted snapshot if it changed since the last write,
+// the previous snapshot is kept as an encrypted backup. A snapshot that was replaced since it was read,
+// e.g. by a sync tool, is not overwritten.
+func flushEncrypted() error {
+	plaintext, err := serializeDB()
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(plaintext)
+	if hash == snapshotHash {
+		return nil
+	}
+	onDisk, err := os.ReadFile(storePath + EncryptedSuffix)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(onDisk) != sealedHash {
+		return fmt.Errorf("%s changed since streakr read it, not overwriting it", storePath+EncryptedSuffix)
+	}
+	sealed, err := sealSnapshot(plaintext, encryptionKey)
+	if err != nil {
+		return err
+	}
+	if err = writeSnapshot(storePath+EncryptedSuffix, sealed); err != nil {
+		return err
+	}
+	snapshotHash = hash
+	sealedHash = sha256.Sum256(sealed)
+	slog.Info("saved encrypted database snapshot", "path", storePath+EncryptedSuffix)
+	return nil
+}
+
+// EncryptStore moves a plain db into an encrypted snapshot and removes the plain db files.
+// The store can't be used afterwards, streakr picks the snapshot up on the next run.
+func EncryptStore(passphrase []byte) error {
+	if encryptionKey != nil {
+		return errors.New("database is already encrypted")
+	}
+	plaintext, err := serializeDB()
+	if err != nil {
+		return err
+	}
+	key, err := newSnapshotKey(passphrase)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealSnapshot(plaintext, key)
+	if err != nil {
+		return err
+	}
+	if err = writeSnapshot(storePath+EncryptedSuffix, sealed); err != nil {
+		return err
+	}
+	if err = db.Close(); err != nil {
+		return err
+	}
+	return removeFiles(storePath, storePath+"-wal", storePath+"-shm")
+}
+
+// DecryptStore writes the in memory db back to a plain db and removes the encrypted snapshot and its backup.
+func DecryptStore() error {
+	if encryptionKey == nil {
+		return errors.New("database is not encrypted")
+	}
+	plaintext, err := serializeDB()
+	if err != nil {
+		return err
+	}
+	if err = writeDBFile(plaintext); err != nil {
+		return err
+	}
+	// nothing left to flush on shutdown
+	encryptionKey = nil
+	return removeFiles(storePath+EncryptedSuffix, storePath+EncryptedSuffix+BackupSuffix)
+}
+
+// writeSnapshot replaces the snapshot at path atomically, hard linking the current one as the backup first.
+func writeSnapshot(path string, sealed []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, sealed, 0600); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err = removeFiles(path + BackupSuffix); err != nil {
+			return err
+		}
+		if err = os.Link(path, path+BackupSuffix); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func removeFiles(paths ...string) error {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// testSnapshotParams are the cheapest argon2id parameters, they keep the tests fast.
+var testSnapshotParams = snapshotParams{time: 1, memory: 8, threads: 1}
+
+// SetEncryptionForTesting makes the store act as encrypted with passphrase, nil makes it plain again.
+// This should ONLY be used in test code
+func SetEncryptionForTesting(passphrase []byte) error {
+	encryptionKey, encryptionPassphrase = nil, nil
+	recordKeys = make(map[string]*snapshotKey)
+	if passphrase == nil {
+		return nil
+	}
+	salt := make([]byte, snapshotSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := deriveSnapshotKey(passphrase, salt, testSnapshotParams)
+	if err != nil {
+		return err
+	}
+	encryptionKey, encryptionPassphrase = key, passphrase
+	recordKeys[string(key.header())] = key
+	return nil
+}
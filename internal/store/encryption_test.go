@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStore_NeverOverwritesOthers(t *testing.T) {
+	ctx := context.Background()
+	passphrase := []byte("hunter2")
+	storePath = filepath.Join(t.TempDir(), "streakr.db")
+	storeLockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() {
+		storeLockTimeout = 5 * time.Second
+		encryptionKey = nil
+		db.Close()
+		lockFile.Close()
+		lockFile, lockedPath = nil, ""
+	})
+
+	require.NoError(t, openMemoryDB(nil))
+	image, err := serializeDB()
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	key, err := deriveSnapshotKey(passphrase, []byte("0123456789abcdef"), testSnapshotParams)
+	require.NoError(t, err)
+	sealed, err := sealSnapshot(image, key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(storePath+EncryptedSuffix, sealed, 0600))
+
+	require.NoError(t, bootstrapEncryptedStore(func() ([]byte, error) { return passphrase, nil }))
+
+	// a second opener is refused while the lock is held, like another process would be
+	held := lockedPath
+	lockedPath = ""
+	err = acquireStoreLock()
+	lockedPath = held
+	require.Error(t, err)
+	assert.Equal(t, se.KindLocked, se.KindOf(err))
+
+	// a snapshot replaced since it was read, e.g. by a sync tool, is not overwritten
+	_, err = GetQueries().AddHabit(ctx, generated.AddHabitParams{Name: "running", Slug: "running", HabitType: HabitTypeImprove})
+	require.NoError(t, err)
+	replaced, err := sealSnapshot(image, key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(storePath+EncryptedSuffix, replaced, 0600))
+	assert.Error(t, flushEncrypted())
+	onDisk, err := os.ReadFile(storePath + EncryptedSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, replaced, onDisk)
+}
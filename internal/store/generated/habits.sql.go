@@ -8,22 +8,56 @@ package generated
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const addHabit = `-- name: AddHabit :one
-INSERT INTO habits (name, description, habit_type, created_at)
-VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+INSERT INTO habits (name, slug, description, habit_type, created_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
 RETURNING id
 `
 
 type AddHabitParams struct {
 	Name        string
+	Slug        string
 	Description sql.NullString
 	HabitType   string
 }
 
 func (q *Queries) AddHabit(ctx context.Context, arg AddHabitParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, addHabit, arg.Name, arg.Description, arg.HabitType)
+	row := q.db.QueryRowContext(ctx, addHabit,
+		arg.Name,
+		arg.Slug,
+		arg.Description,
+		arg.HabitType,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const addHabitWithCreatedAt = `-- name: AddHabitWithCreatedAt :one
+INSERT INTO habits (name, slug, description, habit_type, created_at)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id
+`
+
+type AddHabitWithCreatedAtParams struct {
+	Name        string
+	Slug        string
+	Description sql.NullString
+	HabitType   string
+	CreatedAt   time.Time
+}
+
+func (q *Queries) AddHabitWithCreatedAt(ctx context.Context, arg AddHabitWithCreatedAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, addHabitWithCreatedAt,
+		arg.Name,
+		arg.Slug,
+		arg.Description,
+		arg.HabitType,
+		arg.CreatedAt,
+	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
@@ -89,7 +123,7 @@ func (q *Queries) GetDaysSinceHabitCreation(ctx context.Context, id int64) (int6
 }
 
 const getHabit = `-- name: GetHabit :one
-SELECT id, name, description, habit_type, created_at FROM habits WHERE id = ?
+SELECT id, name, description, habit_type, created_at, cost_per_occurrence, minutes_per_occurrence, slug FROM habits WHERE id = ?
 `
 
 func (q *Queries) GetHabit(ctx context.Context, id int64) (Habit, error) {
@@ -101,12 +135,15 @@ func (q *Queries) GetHabit(ctx context.Context, id int64) (Habit, error) {
 		&i.Description,
 		&i.HabitType,
 		&i.CreatedAt,
+		&i.CostPerOccurrence,
+		&i.MinutesPerOccurrence,
+		&i.Slug,
 	)
 	return i, err
 }
 
 const getHabitByName = `-- name: GetHabitByName :one
-SELECT id, name, description, habit_type, created_at FROM habits WHERE name = ?
+SELECT id, name, description, habit_type, created_at, cost_per_occurrence, minutes_per_occurrence, slug FROM habits WHERE name = ?
 `
 
 func (q *Queries) GetHabitByName(ctx context.Context, name string) (Habit, error) {
@@ -118,12 +155,35 @@ func (q *Queries) GetHabitByName(ctx context.Context, name string) (Habit, error
 		&i.Description,
 		&i.HabitType,
 		&i.CreatedAt,
+		&i.CostPerOccurrence,
+		&i.MinutesPerOccurrence,
+		&i.Slug,
+	)
+	return i, err
+}
+
+const getHabitBySlug = `-- name: GetHabitBySlug :one
+SELECT id, name, description, habit_type, created_at, cost_per_occurrence, minutes_per_occurrence, slug FROM habits WHERE slug = ?
+`
+
+func (q *Queries) GetHabitBySlug(ctx context.Context, slug string) (Habit, error) {
+	row := q.db.QueryRowContext(ctx, getHabitBySlug, slug)
+	var i Habit
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.HabitType,
+		&i.CreatedAt,
+		&i.CostPerOccurrence,
+		&i.MinutesPerOccurrence,
+		&i.Slug,
 	)
 	return i, err
 }
 
 const listHabits = `-- name: ListHabits :many
-SELECT id, name, description, habit_type, created_at FROM habits
+SELECT id, name, description, habit_type, created_at, cost_per_occurrence, minutes_per_occurrence, slug FROM habits
 `
 
 func (q *Queries) ListHabits(ctx context.Context) ([]Habit, error) {
@@ -141,6 +201,9 @@ func (q *Queries) ListHabits(ctx context.Context) ([]Habit, error) {
 			&i.Description,
 			&i.HabitType,
 			&i.CreatedAt,
+			&i.CostPerOccurrence,
+			&i.MinutesPerOccurrence,
+			&i.Slug,
 		); err != nil {
 			return nil, err
 		}
@@ -154,3 +217,20 @@ func (q *Queries) ListHabits(ctx context.Context) ([]Habit, error) {
 	}
 	return items, nil
 }
+
+const updateHabitCosts = `-- name: UpdateHabitCosts :exec
+UPDATE habits
+SET cost_per_occurrence = ?, minutes_per_occurrence = ?
+WHERE id = ?
+`
+
+type UpdateHabitCostsParams struct {
+	CostPerOccurrence    sql.NullFloat64
+	MinutesPerOccurrence sql.NullInt64
+	ID                   int64
+}
+
+func (q *Queries) UpdateHabitCosts(ctx context.Context, arg UpdateHabitCostsParams) error {
+	_, err := q.db.ExecContext(ctx, updateHabitCosts, arg.CostPerOccurrence, arg.MinutesPerOccurrence, arg.ID)
+	return err
+}
@@ -9,12 +9,61 @@ import (
 	"time"
 )
 
+type Achievement struct {
+	ID         int64
+	Code       string
+	HabitID    sql.NullInt64
+	UnlockedAt time.Time
+}
+
+type Goal struct {
+	ID        int64
+	HabitID   int64
+	Kind      string
+	Target    int64
+	StartDate time.Time
+	Deadline  time.Time
+	CreatedAt time.Time
+}
+
 type Habit struct {
-	ID          int64
-	Name        string
-	Description sql.NullString
-	HabitType   string
-	CreatedAt   time.Time
+	ID                   int64
+	Name                 string
+	Description          sql.NullString
+	HabitType            string
+	CreatedAt            time.Time
+	CostPerOccurrence    sql.NullFloat64
+	MinutesPerOccurrence sql.NullInt64
+	Slug                 string
+}
+
+type HabitReminder struct {
+	ID       int64
+	HabitID  int64
+	RemindAt string
+}
+
+type HabitTag struct {
+	HabitID int64
+	TagID   int64
+}
+
+type Routine struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+type RoutineHabit struct {
+	RoutineID int64
+	HabitName string
+	Position  int64
+}
+
+type Slipup struct {
+	HabitID  int64
+	SlipDate time.Time
+	Count    int64
 }
 
 type Streak struct {
@@ -23,3 +72,16 @@ type Streak struct {
 	StreakStart time.Time
 	StreakEnd   time.Time
 }
+
+type SyncOp struct {
+	Device  string
+	Seq     int64
+	Key     string
+	OpTime  int64
+	Payload string
+}
+
+type Tag struct {
+	ID   int64
+	Name string
+}
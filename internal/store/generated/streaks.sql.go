@@ -194,6 +194,58 @@ func (q *Queries) GetTotalStreakDaysQuittingHabit(ctx context.Context, habitID i
 	return total_streak_days, err
 }
 
+const listStreaksForHabit = `-- name: ListStreaksForHabit :many
+SELECT id, habit_id, streak_start, streak_end
+FROM streaks
+WHERE habit_id = ?
+ORDER BY streak_start
+`
+
+func (q *Queries) ListStreaksForHabit(ctx context.Context, habitID int64) ([]Streak, error) {
+	rows, err := q.db.QueryContext(ctx, listStreaksForHabit, habitID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Streak
+	for rows.Next() {
+		var i Streak
+		if err := rows.Scan(
+			&i.ID,
+			&i.HabitID,
+			&i.StreakStart,
+			&i.StreakEnd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateStreak = `-- name: UpdateStreak :exec
+UPDATE streaks
+SET streak_start = ?, streak_end = ?
+WHERE id = ?
+`
+
+type UpdateStreakParams struct {
+	StreakStart time.Time
+	StreakEnd   time.Time
+	ID          int64
+}
+
+func (q *Queries) UpdateStreak(ctx context.Context, arg UpdateStreakParams) error {
+	_, err := q.db.ExecContext(ctx, updateStreak, arg.StreakStart, arg.StreakEnd, arg.ID)
+	return err
+}
+
 const updateStreakEnd = `-- name: UpdateStreakEnd :exec
 UPDATE streaks
 SET streak_end = ?
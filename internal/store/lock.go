@@ -0,0 +1,64 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+)
+
+// LockSuffix is appended to the db path for the lock file of the encrypted and text backends.
+const LockSuffix = ".lock"
+
+// storeLockPoll is how often a waiting writer tries to take the lock again.
+const storeLockPoll = 50 * time.Millisecond
+
+// storeLockTimeout is how long a writer waits for another one to exit, like sqlite's _busy_timeout.
+var storeLockTimeout = 5 * time.Second
+
+var (
+	// lockFile is held open, and flock'ed, for as long as the process uses the store at lockedPath.
+	lockFile   *os.File
+	lockedPath string
+)
+
+// acquireStoreLock takes an exclusive lock next to the db that is held until the process exits.
+// The encrypted and text backends work on a private in memory copy and write it back as a whole,
+// two processes would overwrite each other's changes, so a second one waits for the first to exit and is
+// refused after storeLockTimeout. A read only store is never written back and doesn't need the lock.
+func acquireStoreLock() error {
+	path := storePath + LockSuffix
+	if readOnly || lockedPath == path {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(storeLockTimeout)
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	for errors.Is(err, syscall.EWOULDBLOCK) && time.Now().Before(deadline) {
+		time.Sleep(storeLockPoll)
+		err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	}
+	if err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return &se.StreakrError{
+				TerminalMsg: "the database is in use by another streakr process (e.g. serve), stop it first",
+				Err:         fmt.Errorf("%s is locked: %w", path, err),
+				Kind:        se.KindLocked,
+			}
+		}
+		return err
+	}
+	if lockFile != nil {
+		lockFile.Close()
+	}
+	lockFile = file
+	lockedPath = path
+	return nil
+}
@@ -0,0 +1,26 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireStoreLock_WaitsForOtherWriter(t *testing.T) {
+	storePath = filepath.Join(t.TempDir(), "streakr.db")
+	t.Cleanup(func() {
+		lockFile.Close()
+		lockFile, lockedPath = nil, ""
+	})
+
+	// another writer holds the lock and exits while we wait for it
+	other, err := os.OpenFile(storePath+LockSuffix, os.O_CREATE|os.O_RDWR, 0600)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Flock(int(other.Fd()), syscall.LOCK_EX))
+	time.AfterFunc(200*time.Millisecond, func() { other.Close() })
+	require.NoError(t, acquireStoreLock())
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Atharva21/streakr/internal/shutdown"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
+	"github.com/mattn/go-sqlite3"
+)
+
+// memoryDSN keeps the db in memory only, it must be used through a single connection
+// as every new connection to :memory: would open an empty db.
+const memoryDSN = "file::memory:?_foreign_keys=on"
+
+// inMemory is set when the db is loaded from an encrypted snapshot or plain text files,
+// Flush writes it back to them.
+var inMemory bool
+
+// openMemoryDB opens and migrates the in memory db of the encrypted and text backends,
+// starting from a serialized db image or an empty db if image is nil.
+func openMemoryDB(image []byte) error {
+	if err := setupMemoryDB(image); err != nil {
+		return err
+	}
+	inMemory = true
+	shutdown.RegisterCleanupHook(func() error {
+		if err := Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "could not save the database: %s\n", err.Error())
+			return err
+		}
+		return db.Close()
+	})
+	return nil
+}
+
+// setupMemoryDB makes a new in memory db from image the current db, see openMemoryDB.
+func setupMemoryDB(image []byte) error {
+	memoryDB, err := sql.Open("sqlite3", memoryDSN)
+	if err != nil {
+		return err
+	}
+	memoryDB.SetMaxOpenConns(1)
+	db = memoryDB
+	if image != nil {
+		err = withSQLiteConn(func(conn *sqlite3.SQLiteConn) error {
+			return conn.Deserialize(image, "main")
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = migrateDB(db); err != nil {
+		return &se.StreakrError{Kind: se.KindMigration, Err: fmt.Errorf("could not migrate the database: %w", err)}
+	}
+	queries = generated.New(db)
+	return nil
+}
+
+// reopenMemoryDB replaces the in memory db of a read only store with one made from image and filled
+// by load, the current db is kept if that fails.
+func reopenMemoryDB(image []byte, load func() error) error {
+	previousDB, previousQueries := db, queries
+	err := setupMemoryDB(image)
+	if err == nil && load != nil {
+		err = load()
+	}
+	if err == nil {
+		err = protectReadOnly()
+	}
+	if err != nil {
+		if db != previousDB {
+			db.Close()
+		}
+		db, queries = previousDB, previousQueries
+		return err
+	}
+	return previousDB.Close()
+}
+
+// Reload loads the in memory db of a read only store again if its snapshot or text files changed, so a long
+// running reader like the remind daemon sees what other streakr processes saved meanwhile. It is a no-op
+// for a plain sqlite db, which is read from disk anyway.
+func Reload() error {
+	switch {
+	case !inMemory:
+		return nil
+	case !readOnly:
+		return errors.New("only a read only store can be reloaded, unsaved changes would be lost")
+	case encryptionKey != nil:
+		return reloadEncrypted()
+	case textDir != "":
+		return reloadText()
+	}
+	return nil
+}
+
+// protectReadOnly refuses writes to the loaded in memory db of a read only store, nothing is written
+// back and a write would be lost silently.
+func protectReadOnly() error {
+	if !readOnly {
+		return nil
+	}
+	_, err := db.Exec("PRAGMA query_only = ON")
+	return err
+}
+
+// Flush writes the in memory db back to the encrypted snapshot or the text files,
+// it is a no-op for a plain sqlite db and a read only store.
+func Flush() error {
+	switch {
+	case readOnly:
+		return nil
+	case encryptionKey != nil:
+		return flushEncrypted()
+	case textDir != "":
+		return flushText()
+	}
+	return nil
+}
+
+// serializeDB returns the whole db as a single image. A plain db is switched out of WAL mode first
+// so the image has no pending WAL frames and can be deserialized in memory later.
+func serializeDB() ([]byte, error) {
+	var image []byte
+	err := withSQLiteConn(func(conn *sqlite3.SQLiteConn) error {
+		if !inMemory {
+			if _, err := conn.Exec("PRAGMA journal_mode=DELETE", nil); err != nil {
+				return err
+			}
+		}
+		var err error
+		image, err = conn.Serialize("main")
+		return err
+	})
+	return image, err
+}
+
+// writeDBFile replaces the plain db file with image.
+func writeDBFile(image []byte) error {
+	tmpPath := storePath + ".tmp"
+	if err := os.WriteFile(tmpPath, image, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, storePath)
+}
+
+func withSQLiteConn(fn func(conn *sqlite3.SQLiteConn) error) error {
+	conn, err := GetDB().Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn any) error {
+		sqliteConn, ok := driverConn.(*sqlite3.SQLiteConn)
+		if !ok {
+			return fmt.Errorf("unexpected sqlite connection type %T", driverConn)
+		}
+		return fn(sqliteConn)
+	})
+}
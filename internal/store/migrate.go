@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/golang-migrate/migrate/v4"
+	sqlite3migrate "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// habitSlugsVersion is the migration adding habits.slug, its SQL backfill only approximates
+// util.Slugify so migrateDB redoes it in go.
+const habitSlugsVersion = 8
+
+// migrateDB brings migrationDB up to the latest schema and runs the go steps of the migrations it applied.
+// The driver closes the db it was given on Close, so the migration is never closed here.
+func migrateDB(migrationDB *sql.DB) error {
+	d, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return err
+	}
+	driver, err := sqlite3migrate.WithInstance(migrationDB, &sqlite3migrate.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithInstance("iofs", d, "sqlite3", driver)
+	if err != nil {
+		return err
+	}
+	versionBefore, _, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	if versionBefore < habitSlugsVersion {
+		return backfillHabitSlugs(migrationDB)
+	}
+	return nil
+}
+
+// isSchemaCurrent reports whether the latest migration was applied to currentDB, without writing to it.
+func isSchemaCurrent(currentDB *sql.DB) (bool, error) {
+	latest, err := latestMigrationVersion()
+	if err != nil {
+		return false, err
+	}
+	var tables int
+	err = currentDB.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&tables)
+	if err != nil || tables == 0 {
+		return false, err
+	}
+	var version uint
+	var dirty bool
+	err = currentDB.QueryRow("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return version == latest && !dirty, nil
+}
+
+func latestMigrationVersion() (uint, error) {
+	d, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer d.Close()
+	version, err := d.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := d.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// backfillHabitSlugs sets the slug of every habit to util.Slugify of its name. A habit keeps the slug
+// from the SQL backfill if another habit already has the slugified one.
+func backfillHabitSlugs(migrationDB *sql.DB) error {
+	ctx := context.Background()
+	tx, err := migrationDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	rows, err := tx.QueryContext(ctx, "SELECT id, name, slug FROM habits ORDER BY id")
+	if err != nil {
+		return err
+	}
+	slugs := make(map[int64]string)
+	taken := make(map[string]bool)
+	names := make(map[int64]string)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		var name, slug string
+		if err = rows.Scan(&id, &name, &slug); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+		names[id] = name
+		slugs[id] = slug
+		taken[slug] = true
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		slug := util.Slugify(names[id])
+		if slug == "" || slug == slugs[id] || taken[slug] {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, "UPDATE habits SET slug = ? WHERE id = ?", slug, id); err != nil {
+			return err
+		}
+		delete(taken, slugs[id])
+		taken[slug] = true
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_habit_reminders_habit_id;
+DROP TABLE IF EXISTS habit_reminders;
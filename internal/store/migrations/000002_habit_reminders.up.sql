@@ -0,0 +1,8 @@
+CREATE TABLE habit_reminders (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  habit_id INTEGER NOT NULL,
+  remind_at TEXT NOT NULL CHECK (remind_at GLOB '[0-2][0-9]:[0-5][0-9]'),
+  FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE,
+  UNIQUE (habit_id, remind_at)
+);
+CREATE INDEX idx_habit_reminders_habit_id ON habit_reminders(habit_id);
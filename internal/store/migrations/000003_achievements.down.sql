@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_achievements_code_habit_id;
+DROP TABLE IF EXISTS achievements;
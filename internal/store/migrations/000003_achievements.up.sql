@@ -0,0 +1,9 @@
+CREATE TABLE achievements (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  code TEXT NOT NULL,
+  habit_id INTEGER,
+  unlocked_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+);
+-- habit_id is NULL for achievements that span all habits
+CREATE UNIQUE INDEX idx_achievements_code_habit_id ON achievements(code, COALESCE(habit_id, 0));
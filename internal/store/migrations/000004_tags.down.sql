@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_habit_tags_tag_id;
+DROP TABLE IF EXISTS habit_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS routine_habits;
+DROP TABLE IF EXISTS routines;
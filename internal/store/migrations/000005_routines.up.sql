@@ -0,0 +1,14 @@
+CREATE TABLE routines (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL UNIQUE CHECK (length(name) <= 20),
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- members are kept by name so a deleted habit is reported instead of silently dropped from the routine
+CREATE TABLE routine_habits (
+  routine_id INTEGER NOT NULL,
+  habit_name TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (routine_id, habit_name),
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE
+);
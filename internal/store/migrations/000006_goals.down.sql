@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_goals_habit_id;
+DROP TABLE IF EXISTS goals;
@@ -0,0 +1,11 @@
+CREATE TABLE goals (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  habit_id INTEGER NOT NULL,
+  kind TEXT CHECK (kind IN ('total_days', 'streak', 'rate')) NOT NULL,
+  target INTEGER NOT NULL CHECK (target > 0),
+  start_date DATE NOT NULL,
+  deadline DATE NOT NULL,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_goals_habit_id ON goals(habit_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS slipups;
+ALTER TABLE habits DROP COLUMN minutes_per_occurrence;
+ALTER TABLE habits DROP COLUMN cost_per_occurrence;
@@ -0,0 +1,12 @@
+ALTER TABLE habits ADD COLUMN cost_per_occurrence REAL CHECK (cost_per_occurrence IS NULL OR cost_per_occurrence >= 0);
+ALTER TABLE habits ADD COLUMN minutes_per_occurrence INTEGER CHECK (minutes_per_occurrence IS NULL OR minutes_per_occurrence >= 0);
+
+-- slip-up intensity of quit habits, a slip-up day without a row here counts as a single occurrence.
+-- slip_date is always stored at noon local time so it can be matched exactly.
+CREATE TABLE slipups (
+  habit_id INTEGER NOT NULL,
+  slip_date DATE NOT NULL,
+  count INTEGER NOT NULL CHECK (count > 0),
+  PRIMARY KEY (habit_id, slip_date),
+  FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_habits_slug;
+ALTER TABLE habits DROP COLUMN slug;
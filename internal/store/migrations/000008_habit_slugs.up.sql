@@ -0,0 +1,5 @@
+-- name becomes a free form display name, commands match on the normalized slug.
+-- names so far were single lowercase words, so the slug is the name itself.
+ALTER TABLE habits ADD COLUMN slug TEXT NOT NULL DEFAULT '';
+UPDATE habits SET slug = lower(replace(trim(name), ' ', '-'));
+CREATE UNIQUE INDEX idx_habits_slug ON habits(slug);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sync_ops_key;
+DROP TABLE IF EXISTS sync_ops;
@@ -0,0 +1,14 @@
+-- every change that is shared through `streakr sync`, both the ones made on this device
+-- (device = '') and the ones replayed from other devices.
+-- key identifies what an op changes (a habit, its costs or one of its days), the op with the
+-- latest (op_time, device, seq) for a key wins. op_time is in unix nanoseconds.
+CREATE TABLE sync_ops (
+  device TEXT NOT NULL,
+  seq INTEGER NOT NULL,
+  key TEXT NOT NULL,
+  op_time INTEGER NOT NULL,
+  payload TEXT NOT NULL,
+  PRIMARY KEY (device, seq)
+);
+
+CREATE INDEX idx_sync_ops_key ON sync_ops(key);
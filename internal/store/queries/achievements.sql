@@ -0,0 +1,12 @@
+-- name: UnlockAchievement :execrows
+INSERT OR IGNORE INTO achievements (code, habit_id, unlocked_at)
+VALUES (?, ?, CURRENT_TIMESTAMP);
+
+-- name: ListAchievements :many
+SELECT id, code, habit_id, unlocked_at
+FROM achievements
+ORDER BY unlocked_at;
+
+-- name: RestoreAchievement :exec
+INSERT INTO achievements (code, habit_id, unlocked_at)
+VALUES (?, ?, ?);
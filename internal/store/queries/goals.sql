@@ -0,0 +1,22 @@
+-- name: AddGoal :one
+INSERT INTO goals (habit_id, kind, target, start_date, deadline)
+VALUES (?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: DeleteGoal :execrows
+DELETE FROM goals WHERE id = ?;
+
+-- name: ListGoals :many
+SELECT id, habit_id, kind, target, start_date, deadline, created_at
+FROM goals
+ORDER BY deadline, id;
+
+-- name: ListGoalsForHabit :many
+SELECT id, habit_id, kind, target, start_date, deadline, created_at
+FROM goals
+WHERE habit_id = ?
+ORDER BY deadline, id;
+
+-- name: RestoreGoal :exec
+INSERT INTO goals (id, habit_id, kind, target, start_date, deadline, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
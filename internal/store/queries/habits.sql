@@ -1,6 +1,11 @@
 -- name: AddHabit :one
-INSERT INTO habits (name, description, habit_type, created_at)
-VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+INSERT INTO habits (name, slug, description, habit_type, created_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+RETURNING id;
+
+-- name: AddHabitWithCreatedAt :one
+INSERT INTO habits (name, slug, description, habit_type, created_at)
+VALUES (?, ?, ?, ?, ?)
 RETURNING id;
 
 -- name: GetHabit :one
@@ -9,6 +14,9 @@ SELECT * FROM habits WHERE id = ?;
 -- name: GetHabitByName :one
 SELECT * FROM habits WHERE name = ?;
 
+-- name: GetHabitBySlug :one
+SELECT * FROM habits WHERE slug = ?;
+
 -- name: ListHabits :many
 SELECT * FROM habits;
 
@@ -34,3 +42,8 @@ WHERE h.habit_type = 'improve'
 SELECT CAST(1 + julianday('now') - julianday(DATE(created_at)) AS INTEGER) as days_passed
 FROM habits
 WHERE id = ?;
+
+-- name: UpdateHabitCosts :exec
+UPDATE habits
+SET cost_per_occurrence = ?, minutes_per_occurrence = ?
+WHERE id = ?;
@@ -0,0 +1,18 @@
+-- name: AddReminder :exec
+INSERT INTO habit_reminders (habit_id, remind_at)
+VALUES (?, ?);
+
+-- name: DeleteRemindersForHabit :exec
+DELETE FROM habit_reminders
+WHERE habit_id = ?;
+
+-- name: ListRemindersForHabit :many
+SELECT remind_at
+FROM habit_reminders
+WHERE habit_id = ?
+ORDER BY remind_at;
+
+-- name: ListReminders :many
+SELECT id, habit_id, remind_at
+FROM habit_reminders
+ORDER BY remind_at;
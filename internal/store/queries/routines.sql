@@ -0,0 +1,24 @@
+-- name: CreateRoutine :one
+INSERT INTO routines (name)
+VALUES (?)
+RETURNING id;
+
+-- name: AddRoutineHabit :exec
+INSERT INTO routine_habits (routine_id, habit_name, position)
+VALUES (?, ?, ?);
+
+-- name: DeleteRoutine :execrows
+DELETE FROM routines WHERE name = ?;
+
+-- name: ListRoutineHabits :many
+SELECT r.name, rh.habit_name
+FROM routines r
+JOIN routine_habits rh ON r.id = rh.routine_id
+ORDER BY r.name, rh.position;
+
+-- name: ListRoutineHabitsByName :many
+SELECT rh.habit_name
+FROM routines r
+JOIN routine_habits rh ON r.id = rh.routine_id
+WHERE r.name = ?
+ORDER BY rh.position;
@@ -0,0 +1,14 @@
+-- name: AddSlipupCount :exec
+INSERT INTO slipups (habit_id, slip_date, count)
+VALUES (?, ?, ?)
+ON CONFLICT (habit_id, slip_date) DO UPDATE SET count = count + excluded.count;
+
+-- name: DeleteSlipup :exec
+DELETE FROM slipups
+WHERE habit_id = ? AND slip_date = ?;
+
+-- name: ListSlipupsForHabit :many
+SELECT habit_id, slip_date, count
+FROM slipups
+WHERE habit_id = ?
+ORDER BY slip_date;
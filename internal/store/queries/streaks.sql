@@ -8,6 +8,11 @@ UPDATE streaks
 SET streak_end = ?
 WHERE id = ?;
 
+-- name: UpdateStreak :exec
+UPDATE streaks
+SET streak_start = ?, streak_end = ?
+WHERE id = ?;
+
 -- name: GetLatestStreak :one
 SELECT id, habit_id, streak_start, streak_end
 FROM streaks
@@ -59,3 +64,9 @@ WHERE habit_id = ?;
 SELECT CAST(COALESCE(SUM(julianday(DATE(streak_end)) - julianday(DATE(streak_start))), 0) AS INTEGER) as total_streak_days
 FROM streaks 
 WHERE habit_id = ?;
+
+-- name: ListStreaksForHabit :many
+SELECT id, habit_id, streak_start, streak_end
+FROM streaks
+WHERE habit_id = ?
+ORDER BY streak_start;
@@ -0,0 +1,33 @@
+-- name: AddLocalSyncOp :one
+INSERT INTO sync_ops (device, seq, key, op_time, payload)
+SELECT '', COALESCE(MAX(seq), 0) + 1, ?, ?, ?
+FROM sync_ops
+WHERE device = ''
+RETURNING seq;
+
+-- name: AddSyncOp :exec
+INSERT INTO sync_ops (device, seq, key, op_time, payload)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetLatestSyncOpForKey :one
+SELECT device, seq, key, op_time, payload
+FROM sync_ops
+WHERE key = ?
+ORDER BY op_time DESC, device DESC, seq DESC
+LIMIT 1;
+
+-- name: GetMaxSyncSeqForDevice :one
+SELECT CAST(COALESCE(MAX(seq), 0) AS INTEGER) AS max_seq
+FROM sync_ops
+WHERE device = ?;
+
+-- name: ListLocalSyncOpsAfter :many
+SELECT device, seq, key, op_time, payload
+FROM sync_ops
+WHERE device = '' AND seq > ?
+ORDER BY seq;
+
+-- name: ListSyncOps :many
+SELECT device, seq, key, op_time, payload
+FROM sync_ops
+ORDER BY device, seq;
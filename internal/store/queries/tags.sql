@@ -0,0 +1,51 @@
+-- name: UpsertTag :one
+INSERT INTO tags (name)
+VALUES (?)
+ON CONFLICT (name) DO UPDATE SET name = excluded.name
+RETURNING id;
+
+-- name: AddHabitTag :exec
+INSERT OR IGNORE INTO habit_tags (habit_id, tag_id)
+VALUES (?, ?);
+
+-- name: RemoveHabitTag :exec
+DELETE FROM habit_tags
+WHERE habit_id = ? AND tag_id = (SELECT id FROM tags WHERE name = ?);
+
+-- name: DeleteUnusedTags :exec
+DELETE FROM tags
+WHERE id NOT IN (SELECT DISTINCT tag_id FROM habit_tags);
+
+-- name: ListTags :many
+SELECT name FROM tags ORDER BY name;
+
+-- name: ListTagsForHabit :many
+SELECT t.name
+FROM tags t
+JOIN habit_tags ht ON t.id = ht.tag_id
+WHERE ht.habit_id = ?
+ORDER BY t.name;
+
+-- name: ListHabitsByTag :many
+SELECT h.id, h.name, h.description, h.habit_type, h.created_at, h.cost_per_occurrence, h.minutes_per_occurrence, h.slug
+FROM habits h
+JOIN habit_tags ht ON h.id = ht.habit_id
+JOIN tags t ON t.id = ht.tag_id
+WHERE t.name = ?;
+
+-- name: CountTotalImproveHabitsByTag :one
+SELECT COUNT(*) as total_improve_habits
+FROM habits h
+JOIN habit_tags ht ON h.id = ht.habit_id
+JOIN tags t ON t.id = ht.tag_id
+WHERE h.habit_type = 'improve' AND t.name = ?;
+
+-- name: CountImproveHabitsLoggedTodayByTag :one
+SELECT COUNT(DISTINCT h.id) as logged_today_count
+FROM habits h
+JOIN streaks s ON h.id = s.habit_id
+JOIN habit_tags ht ON h.id = ht.habit_id
+JOIN tags t ON t.id = ht.tag_id
+WHERE h.habit_type = 'improve'
+ AND DATE(s.streak_end) = DATE('now')
+ AND t.name = ?;
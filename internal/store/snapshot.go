@@ -0,0 +1,144 @@
+package store
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// An encrypted snapshot is the serialized sqlite db sealed with XChaCha20-Poly1305:
+//
+//	magic (8) | argon2 time (4, big endian) | argon2 memory in KiB (4, big endian) | argon2 threads (1) |
+//	salt (16) | nonce (24) | ciphertext
+//
+// the key is derived from the passphrase with argon2id, and the header is
+// authenticated as additional data so the parameters can't be swapped.
+var snapshotMagic = []byte("STRKENC1")
+
+const (
+	snapshotSaltSize   = 16
+	snapshotKeySize    = chacha20poly1305.KeySize
+	snapshotHeaderSize = 8 + 4 + 4 + 1 + snapshotSaltSize
+	// snapshotMaxMemory bounds the memory a header can ask for, in KiB, so a corrupted one can't exhaust it
+	// before the snapshot fails to authenticate.
+	snapshotMaxMemory = 1024 * 1024
+)
+
+// snapshotParams are the argon2id cost parameters a key is derived with, kept in the header so they
+// can be raised later without breaking existing snapshots.
+type snapshotParams struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// defaultSnapshotParams follow the second recommended option of RFC 9106.
+var defaultSnapshotParams = snapshotParams{time: 3, memory: 64 * 1024, threads: 4}
+
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted encrypted database")
+
+// snapshotKey is derived once per run and reused for every snapshot written,
+// each write still gets a fresh nonce.
+type snapshotKey struct {
+	params snapshotParams
+	salt   []byte
+	key    []byte
+}
+
+func newSnapshotKey(passphrase []byte) (*snapshotKey, error) {
+	salt := make([]byte, snapshotSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return deriveSnapshotKey(passphrase, salt, defaultSnapshotParams)
+}
+
+func deriveSnapshotKey(passphrase, salt []byte, params snapshotParams) (*snapshotKey, error) {
+	if params.time == 0 || params.threads == 0 || params.memory < 8*uint32(params.threads) || params.memory > snapshotMaxMemory {
+		return nil, fmt.Errorf("invalid argon2 parameters in the encrypted database header")
+	}
+	key := argon2.IDKey(passphrase, salt, params.time, params.memory, params.threads, snapshotKeySize)
+	return &snapshotKey{params: params, salt: salt, key: key}, nil
+}
+
+func (k *snapshotKey) header() []byte {
+	header := make([]byte, 0, snapshotHeaderSize)
+	header = append(header, snapshotMagic...)
+	header = binary.BigEndian.AppendUint32(header, k.params.time)
+	header = binary.BigEndian.AppendUint32(header, k.params.memory)
+	header = append(header, k.params.threads)
+	return append(header, k.salt...)
+}
+
+func (k *snapshotKey) aead() (cipher.AEAD, error) {
+	return chacha20poly1305.NewX(k.key)
+}
+
+func sealSnapshot(plaintext []byte, key *snapshotKey) ([]byte, error) {
+	aead, err := key.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header := key.header()
+	sealed := append(header, nonce...)
+	return aead.Seal(sealed, nonce, plaintext, header), nil
+}
+
+// openSnapshot decrypts a snapshot, returning the key so later snapshots can be sealed without deriving it again.
+func openSnapshot(data, passphrase []byte) ([]byte, *snapshotKey, error) {
+	params, salt, err := parseSnapshotHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := deriveSnapshotKey(passphrase, salt, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := openSnapshotWithKey(data, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plaintext, key, nil
+}
+
+func parseSnapshotHeader(data []byte) (snapshotParams, []byte, error) {
+	if len(data) < snapshotHeaderSize || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic) {
+		return snapshotParams{}, nil, fmt.Errorf("not an encrypted streakr database")
+	}
+	rest := data[len(snapshotMagic):]
+	params := snapshotParams{
+		time:    binary.BigEndian.Uint32(rest),
+		memory:  binary.BigEndian.Uint32(rest[4:]),
+		threads: rest[8],
+	}
+	salt := bytes.Clone(rest[9:][:snapshotSaltSize])
+	return params, salt, nil
+}
+
+// openSnapshotWithKey decrypts a snapshot with a key derived from its header.
+func openSnapshotWithKey(data []byte, key *snapshotKey) ([]byte, error) {
+	aead, err := key.aead()
+	if err != nil {
+		return nil, err
+	}
+	header := data[:snapshotHeaderSize]
+	rest := data[snapshotHeaderSize:]
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	key, err := deriveSnapshotKey([]byte("hunter2"), []byte("0123456789abcdef"), testSnapshotParams)
+	require.NoError(t, err)
+	plaintext := []byte("SQLite format 3\x00 habits")
+
+	sealed, err := sealSnapshot(plaintext, key)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "habits")
+
+	opened, openedKey, err := openSnapshot(sealed, []byte("hunter2"))
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+	assert.Equal(t, key.key, openedKey.key)
+	assert.Equal(t, testSnapshotParams, openedKey.params)
+
+	// every snapshot gets a fresh nonce
+	again, err := sealSnapshot(plaintext, key)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+}
+
+func TestSnapshot_Rejected(t *testing.T) {
+	key, err := deriveSnapshotKey([]byte("hunter2"), []byte("0123456789abcdef"), testSnapshotParams)
+	require.NoError(t, err)
+	sealed, err := sealSnapshot([]byte("habits"), key)
+	require.NoError(t, err)
+
+	_, _, err = openSnapshot(sealed, []byte("hunter3"))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	tampered := append([]byte{}, sealed...)
+	tampered[len(tampered)-1] ^= 1
+	_, _, err = openSnapshot(tampered, []byte("hunter2"))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// the argon2 parameters are authenticated with the data
+	tampered = append([]byte{}, sealed...)
+	tampered[len(snapshotMagic)+7] ^= 1
+	_, _, err = openSnapshot(tampered, []byte("hunter2"))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// a header asking for more memory than any real one is refused before deriving the key
+	tampered = append([]byte{}, sealed...)
+	tampered[len(snapshotMagic)+4] = 0xff
+	_, _, err = openSnapshot(tampered, []byte("hunter2"))
+	assert.ErrorContains(t, err, "invalid argon2 parameters")
+
+	_, _, err = openSnapshot([]byte("SQLite format 3\x00"), []byte("hunter2"))
+	assert.Error(t, err)
+}
@@ -3,18 +3,16 @@ package store
 import (
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
 
 	"github.com/Atharva21/streakr/internal/shutdown"
 	"github.com/Atharva21/streakr/internal/store/generated"
-	"github.com/Atharva21/streakr/internal/util"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	se "github.com/Atharva21/streakr/internal/streakrerror"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
 //go:embed migrations
@@ -25,61 +23,189 @@ const (
 	HabitTypeQuit    = "quit"
 )
 
+const (
+	GoalKindTotalDays = "total_days"
+	GoalKindStreak    = "streak"
+	GoalKindRate      = "rate"
+)
+
+// dsnParams enables foreign keys, and lets concurrent readers and writers (e.g. the http server)
+// share the db file instead of failing with "database is locked". Transactions take the write lock
+// when they begin, a transaction that read first could not wait for it and would fail instead.
+const dsnParams = "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+
+// readOnlyDSNParams opens the db for reading only, the journal mode is left to the writers.
+const readOnlyDSNParams = "?mode=ro&_foreign_keys=on&_busy_timeout=5000"
+
 var (
 	bootstrapStoreOnce sync.Once
+	bootstrapStoreErr  error
+	storePath          string
 	db                 *sql.DB
 	queries            *generated.Queries
+	// readOnly is set for commands that only read, so they never hold a lock a concurrent writer
+	// has to wait for: a plain db is opened with mode=ro, the in memory backends are never written back.
+	readOnly bool
 )
 
-func BootstrapStore(dbPath string) {
+// BootstrapStore opens the db at dbPath and migrates it, for reading only if readOnly is set. An encrypted
+// snapshot next to it is opened instead with the passphrase from passphrase, and without a db the text
+// storage in textPath is used if present. Only the first call does anything, later ones return its error.
+func BootstrapStore(dbPath, textPath string, readOnly bool, passphrase PassphraseFunc) error {
 	bootstrapStoreOnce.Do(func() {
-		file, err := os.OpenFile(dbPath, os.O_CREATE|os.O_RDWR, 0600)
-		if err != nil {
-			util.ErrorAndExitGeneric(err)
-		}
-		err = file.Close()
-		if err != nil {
-			util.ErrorAndExitGeneric(err)
-		}
-		db, err = sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
-		if err != nil {
-			util.ErrorAndExitGeneric(err)
-		}
-		shutdown.RegisterCleanupHook(func() error {
-			return db.Close()
-		})
-		if err = db.Ping(); err != nil {
-			util.ErrorAndExitGeneric(err)
-		}
+		bootstrapStoreErr = bootstrapStore(dbPath, textPath, readOnly, passphrase)
+	})
+	return bootstrapStoreErr
+}
+
+func bootstrapStore(dbPath, textPath string, ro bool, passphrase PassphraseFunc) error {
+	storePath = dbPath
+	readOnly = ro
+	if _, err := os.Stat(dbPath + EncryptedSuffix); err == nil {
+		return bootstrapEncryptedStore(passphrase)
+	}
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) && isTextDir(textPath) {
+		return bootstrapTextStore(textPath)
+	}
+	fileDB, err := openMigratedFileDB(dbPath, readOnly)
+	if err != nil {
+		return err
+	}
+	db = fileDB
+	shutdown.RegisterCleanupHook(func() error {
+		return db.Close()
+	})
+	queries = generated.New(db)
+	return nil
+}
+
+// ModTime returns the latest modification time of the store BootstrapStore would open for dbPath and
+// textPath without opening it: the encrypted snapshot, the text files, or the db and its write-ahead log
+// where recent writes live until they are checkpointed.
+func ModTime(dbPath, textPath string) (int64, error) {
+	if _, err := os.Stat(dbPath + EncryptedSuffix); err == nil {
+		return latestModTime(dbPath + EncryptedSuffix)
+	}
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) && isTextDir(textPath) {
+		return textModTime(textPath)
+	}
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return 0, err
+	}
+	modTime := info.ModTime().UnixNano()
+	if walInfo, err := os.Stat(dbPath + "-wal"); err == nil && walInfo.ModTime().UnixNano() > modTime {
+		modTime = walInfo.ModTime().UnixNano()
+	}
+	return modTime, nil
+}
 
-		d, err := iofs.New(migrationsFS, "migrations")
+// latestModTime returns the latest modification time of paths, all of which must exist.
+func latestModTime(paths ...string) (int64, error) {
+	var latest int64
+	for _, path := range paths {
+		info, err := os.Stat(path)
 		if err != nil {
-			util.ErrorAndExitGeneric(err)
+			return 0, err
 		}
-		m, err := migrate.NewWithSourceInstance("iofs", d, "sqlite3://"+dbPath+"?_foreign_keys=on")
+		latest = max(latest, info.ModTime().UnixNano())
+	}
+	return latest, nil
+}
+
+// openMigratedFileDB opens the db file at path with the latest schema. A read only db is opened with
+// mode=ro right away if its schema is current, the db is only opened for writing to create or migrate it.
+func openMigratedFileDB(path string, readOnly bool) (*sql.DB, error) {
+	if readOnly {
+		readOnlyDB, err := openCurrentReadOnly(path)
 		if err != nil {
-			util.ErrorAndExitGeneric(err)
+			return nil, fmt.Errorf("could not open %s: %w", path, err)
 		}
-		defer m.Close()
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-			util.ErrorAndExitGeneric(err)
+		if readOnlyDB != nil {
+			return readOnlyDB, nil
 		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err = file.Close(); err != nil {
+		return nil, err
+	}
+	fileDB, err := openFileDB(path, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", path, err)
+	}
+	if err = migrateDB(fileDB); err != nil {
+		fileDB.Close()
+		return nil, &se.StreakrError{Kind: se.KindMigration, Err: fmt.Errorf("could not migrate %s: %w", path, err)}
+	}
+	if !readOnly {
+		return fileDB, nil
+	}
+	if err = fileDB.Close(); err != nil {
+		return nil, err
+	}
+	if fileDB, err = openFileDB(path, true); err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", path, err)
+	}
+	return fileDB, nil
+}
+
+// openCurrentReadOnly opens the db at path for reading only, it returns nil if the db doesn't exist
+// or needs to be migrated first.
+func openCurrentReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	readOnlyDB, err := openFileDB(path, true)
+	if err != nil {
+		return nil, err
+	}
+	current, err := isSchemaCurrent(readOnlyDB)
+	if err != nil || !current {
+		readOnlyDB.Close()
+		return nil, err
+	}
+	return readOnlyDB, nil
+}
 
-		queries = generated.New(db)
+// openFileDB opens and pings the sqlite db at path, for reading only if readOnly is set.
+func openFileDB(path string, readOnly bool) (*sql.DB, error) {
+	dsn := path + dsnParams
+	if readOnly {
+		dsn = "file:" + path + readOnlyDSNParams
+	}
+	fileDB, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err = fileDB.Ping(); err != nil {
+		fileDB.Close()
+		return nil, err
+	}
+	return fileDB, nil
+}
 
-	})
+// ClassifyError is the kind of err, with sqlite's busy and locked errors as KindLocked.
+func ClassifyError(err error) se.Kind {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+		return se.KindLocked
+	}
+	return se.KindOf(err)
 }
 
 func GetDB() *sql.DB {
 	if db == nil {
-		util.ErrorAndExitGeneric(fmt.Errorf("DB not initialized, GetDB called before bootstrapStore"))
+		panic("DB is not initialized. Call BootstrapStore() first.")
 	}
 	return db
 }
 
 func GetQueries() *generated.Queries {
 	if queries == nil {
-		util.ErrorAndExitGeneric(fmt.Errorf("Queries instance not found, GetQueries called before code is generated"))
+		panic("Queries are not initialized. Call BootstrapStore() first.")
 	}
 	return queries
 }
@@ -90,6 +216,13 @@ func SetQueriesForTesting(testQueries *generated.Queries) {
 	queries = testQueries
 }
 
+// OpenFileDBForTesting opens the db file at path with the latest schema like BootstrapStore does,
+// for reading only if readOnly is set.
+// This should ONLY be used in test code
+func OpenFileDBForTesting(path string, readOnly bool) (*sql.DB, error) {
+	return openMigratedFileDB(path, readOnly)
+}
+
 // SetDBForTesting allows tests to inject a custom database instance
 // This should ONLY be used in test code
 func SetDBForTesting(testDB *sql.DB) {
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/golang-migrate/migrate/v4"
+	sqlite3migrate "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenMigratedFileDB_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "streakr.db")
+
+	// a missing db is created and migrated before it is opened for reading
+	readOnlyDB, err := openMigratedFileDB(path, true)
+	require.NoError(t, err)
+	current, err := isSchemaCurrent(readOnlyDB)
+	require.NoError(t, err)
+	assert.True(t, current)
+	_, err = generated.New(readOnlyDB).ListHabits(ctx)
+	assert.NoError(t, err)
+	_, err = generated.New(readOnlyDB).AddHabit(ctx, generated.AddHabitParams{Name: "running", Slug: "running", HabitType: HabitTypeImprove})
+	assert.Error(t, err)
+	require.NoError(t, readOnlyDB.Close())
+
+	// an outdated db is migrated first
+	fileDB, err := openFileDB(path, false)
+	require.NoError(t, err)
+	_, err = fileDB.ExecContext(ctx, "UPDATE schema_migrations SET version = version - 1")
+	require.NoError(t, err)
+	current, err = isSchemaCurrent(fileDB)
+	require.NoError(t, err)
+	assert.False(t, current)
+	require.NoError(t, fileDB.Close())
+	readOnlyDB, err = openCurrentReadOnly(path)
+	require.NoError(t, err)
+	assert.Nil(t, readOnlyDB)
+}
+
+func TestMigrateDB_BackfillsSlugs(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "streakr.db")
+	fileDB, err := openFileDB(path, false)
+	require.NoError(t, err)
+	defer fileDB.Close()
+
+	// a db from before habits had slugs
+	d, err := iofs.New(migrationsFS, "migrations")
+	require.NoError(t, err)
+	driver, err := sqlite3migrate.WithInstance(fileDB, &sqlite3migrate.Config{})
+	require.NoError(t, err)
+	m, err := migrate.NewWithInstance("iofs", d, "sqlite3", driver)
+	require.NoError(t, err)
+	require.NoError(t, m.Migrate(habitSlugsVersion-1))
+	for _, name := range []string{"ÉVENING  Walk", "run fast", "Run_Fast", "gym"} {
+		_, err = fileDB.ExecContext(ctx, "INSERT INTO habits (name) VALUES (?)", name)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, migrateDB(fileDB))
+	habits, err := generated.New(fileDB).ListHabits(ctx)
+	require.NoError(t, err)
+	slugs := make(map[string]string)
+	for _, habit := range habits {
+		slugs[habit.Name] = habit.Slug
+	}
+	assert.Equal(t, map[string]string{
+		"ÉVENING  Walk": "évening-walk",
+		"run fast":      "run-fast",
+		// run-fast is taken, the sql backfill is kept
+		"Run_Fast": "run_fast",
+		"gym":      "gym",
+	}, slugs)
+}
+
+func TestModTime_FollowsBackend(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "streakr.db")
+	textPath := filepath.Join(dir, "text")
+	touch := func(path string, at time.Time) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, nil, 0600))
+		require.NoError(t, os.Chtimes(path, at, at))
+	}
+	base := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	_, err := ModTime(dbPath, textPath)
+	assert.Error(t, err)
+
+	// text storage is used without a db, the latest of its files counts
+	touch(filepath.Join(textPath, textHabitsDir, "running.yaml"), base)
+	touch(filepath.Join(textPath, "2026.log"), base.Add(2*time.Hour))
+	touch(filepath.Join(textPath, "notes.txt"), base.Add(3*time.Hour))
+	for _, path := range []string{textPath, filepath.Join(textPath, textHabitsDir)} {
+		require.NoError(t, os.Chtimes(path, base, base))
+	}
+	modTime, err := ModTime(dbPath, textPath)
+	require.NoError(t, err)
+	assert.Equal(t, base.Add(2*time.Hour).UnixNano(), modTime)
+
+	// a db wins over text storage, its write-ahead log is included
+	touch(dbPath, base.Add(4*time.Hour))
+	touch(dbPath+"-wal", base.Add(5*time.Hour))
+	modTime, err = ModTime(dbPath, textPath)
+	require.NoError(t, err)
+	assert.Equal(t, base.Add(5*time.Hour).UnixNano(), modTime)
+
+	// an encrypted snapshot wins over both
+	touch(dbPath+EncryptedSuffix, base.Add(time.Hour))
+	modTime, err = ModTime(dbPath, textPath)
+	require.NoError(t, err)
+	assert.Equal(t, base.Add(time.Hour).UnixNano(), modTime)
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/util"
+)
+
+// LoggedDays flattens streak ranges back into the days the user logged.
+// for improve habits these are the performed days, for quit habits the slip-up days (streak ends).
+func LoggedDays(habit generated.Habit, streaks []generated.Streak) []time.Time {
+	loggedDays := make([]time.Time, 0)
+	for _, streak := range streaks {
+		if habit.HabitType == HabitTypeQuit {
+			loggedDays = append(loggedDays, streak.StreakEnd)
+			continue
+		}
+		for date := streak.StreakStart; util.CompareDate(date, streak.StreakEnd) >= 0; date = util.GetNextDayOf(date) {
+			loggedDays = append(loggedDays, date)
+		}
+	}
+	return loggedDays
+}
+
+// BuildStreaks is the inverse of LoggedDays, it turns sorted, de-duplicated logged days into streak ranges
+// following the same rules service.LogHabitsForToday uses.
+func BuildStreaks(habit generated.Habit, loggedDays []time.Time) []generated.AddStreakParams {
+	streaks := make([]generated.AddStreakParams, 0)
+	for i, day := range loggedDays {
+		if habit.HabitType == HabitTypeQuit {
+			// clean days run from the day after the previous slip-up (or habit creation) up to this slip-up.
+			start := util.GetNextDayOf(habit.CreatedAt)
+			if i > 0 {
+				start = util.GetNextDayOf(loggedDays[i-1])
+			}
+			if util.CompareDate(start, day) == -1 {
+				start = day
+			}
+			streaks = append(streaks, generated.AddStreakParams{HabitID: habit.ID, StreakStart: start, StreakEnd: day})
+			continue
+		}
+		if i > 0 && util.GetDayDiff(loggedDays[i-1], day) == 1 {
+			streaks[len(streaks)-1].StreakEnd = day
+			continue
+		}
+		streaks = append(streaks, generated.AddStreakParams{HabitID: habit.ID, StreakStart: day, StreakEnd: day})
+	}
+	return streaks
+}
@@ -0,0 +1,785 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// The text backend keeps everything as plain files meant to be diffed and committed to git:
+//
+//	habits/<slug>.yaml  one file per habit with its settings, tags, reminders, goals and achievements
+//	<year>.log          a ledger line per logged day ("2026-10-19 running"), slip-up days for quit habits
+//	                    with "x3" for repeated slip-ups
+//	routines.yaml, achievements.yaml (not tied to a habit) and sync_ops.jsonl
+//
+// The files are loaded into an in memory db on start, so the text backend serves the same
+// generated.Queries as sqlite, and are written back by Flush.
+const (
+	textHabitsDir        = "habits"
+	textHabitExt         = ".yaml"
+	textLedgerExt        = ".log"
+	textRoutinesFile     = "routines.yaml"
+	textAchievementsFile = "achievements.yaml"
+	textSyncOpsFile      = "sync_ops.jsonl"
+	textDayLayout        = "2006-01-02"
+)
+
+var textLedgerName = regexp.MustCompile(`^[0-9]{4}\.log$`)
+
+var (
+	// textDir is set while the text backend is used.
+	textDir string
+	// textFiles holds the managed files of textDir as this process loaded or last wrote them,
+	// flushText only writes or removes files whose content this process changed.
+	textFiles map[string][]byte
+)
+
+type textHabit struct {
+	Name                 string            `yaml:"name"`
+	Type                 string            `yaml:"type"`
+	Description          string            `yaml:"description,omitempty"`
+	CreatedAt            time.Time         `yaml:"created_at"`
+	CostPerOccurrence    float64           `yaml:"cost_per_occurrence,omitempty"`
+	MinutesPerOccurrence int64             `yaml:"minutes_per_occurrence,omitempty"`
+	Tags                 []string          `yaml:"tags,omitempty"`
+	Reminders            []string          `yaml:"reminders,omitempty"`
+	Goals                []textGoal        `yaml:"goals,omitempty"`
+	Achievements         []textAchievement `yaml:"achievements,omitempty"`
+}
+
+type textGoal struct {
+	ID        int64     `yaml:"id"`
+	Kind      string    `yaml:"kind"`
+	Target    int64     `yaml:"target"`
+	Start     string    `yaml:"start"`
+	Deadline  string    `yaml:"deadline"`
+	CreatedAt time.Time `yaml:"created_at"`
+}
+
+type textAchievement struct {
+	Code       string    `yaml:"code"`
+	UnlockedAt time.Time `yaml:"unlocked_at"`
+}
+
+type textRoutine struct {
+	Name   string   `yaml:"name"`
+	Habits []string `yaml:"habits"`
+}
+
+type textSyncOp struct {
+	Device  string          `json:"device"`
+	Seq     int64           `json:"seq"`
+	Key     string          `json:"key"`
+	OpTime  int64           `json:"op_time"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func IsText() bool {
+	return textDir != ""
+}
+
+func isTextDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, textHabitsDir))
+	return err == nil && info.IsDir()
+}
+
+func bootstrapTextStore(dir string) error {
+	if err := acquireStoreLock(); err != nil {
+		return err
+	}
+	if err := openMemoryDB(nil); err != nil {
+		return err
+	}
+	files, err := readTextFiles(dir)
+	if err != nil {
+		return fmt.Errorf("could not load text storage from %s: %w", dir, err)
+	}
+	if err = loadText(context.Background(), dir); err != nil {
+		return fmt.Errorf("could not load text storage from %s: %w", dir, err)
+	}
+	if err = protectReadOnly(); err != nil {
+		return err
+	}
+	textDir = dir
+	textFiles = files
+	return nil
+}
+
+// reloadText loads the files into a new in memory db if any of them changed since they were read.
+func reloadText() error {
+	files, err := readTextFiles(textDir)
+	if err != nil {
+		return fmt.Errorf("could not load text storage from %s: %w", textDir, err)
+	}
+	if maps.EqualFunc(files, textFiles, bytes.Equal) {
+		return nil
+	}
+	err = reopenMemoryDB(nil, func() error {
+		return loadText(context.Background(), textDir)
+	})
+	if err != nil {
+		return fmt.Errorf("could not load text storage from %s: %w", textDir, err)
+	}
+	textFiles = files
+	return nil
+}
+
+func flushText() error {
+	return dumpText(context.Background(), GetQueries(), textDir, textFiles)
+}
+
+// MigrateToText writes the sqlite db to text files in dir, which are used from then on.
+// The db file is kept as streakr.db.bak.
+func MigrateToText(dir string) error {
+	if encryptionKey != nil {
+		return errors.New("text storage can't be encrypted, decrypt the database first")
+	}
+	if textDir != "" {
+		return errors.New("already using text storage")
+	}
+	if isTextDir(dir) {
+		return fmt.Errorf("%s already holds text storage", dir)
+	}
+	if _, err := os.Stat(storePath + BackupSuffix); err == nil {
+		return fmt.Errorf("%s is in the way, move it first", storePath+BackupSuffix)
+	}
+	if err := dumpText(context.Background(), GetQueries(), dir, make(map[string][]byte)); err != nil {
+		return err
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(storePath, storePath+BackupSuffix); err != nil {
+		return err
+	}
+	return removeFiles(storePath+"-wal", storePath+"-shm")
+}
+
+// MigrateToSQLite writes the text storage to the sqlite db, which is used from then on.
+// The text files are left as they are.
+func MigrateToSQLite() error {
+	if textDir == "" {
+		return errors.New("already using sqlite storage")
+	}
+	image, err := serializeDB()
+	if err != nil {
+		return err
+	}
+	if err = writeDBFile(image); err != nil {
+		return err
+	}
+	// nothing left to flush on shutdown
+	textDir = ""
+	return nil
+}
+
+// dumpText writes the db to dir, see writeTextFiles for known.
+func dumpText(appContext context.Context, q *generated.Queries, dir string, known map[string][]byte) error {
+	files := make(map[string][]byte)
+	habits, err := q.ListHabits(appContext)
+	if err != nil {
+		return err
+	}
+	slugsByID := make(map[int64]string)
+	for _, habit := range habits {
+		slugsByID[habit.ID] = habit.Slug
+	}
+	goalsByHabitID := make(map[int64][]textGoal)
+	goals, err := q.ListGoals(appContext)
+	if err != nil {
+		return err
+	}
+	for _, goal := range goals {
+		goalsByHabitID[goal.HabitID] = append(goalsByHabitID[goal.HabitID], textGoal{
+			ID:        goal.ID,
+			Kind:      goal.Kind,
+			Target:    goal.Target,
+			Start:     goal.StartDate.Format(textDayLayout),
+			Deadline:  goal.Deadline.Format(textDayLayout),
+			CreatedAt: goal.CreatedAt,
+		})
+	}
+	achievementsByHabitID := make(map[int64][]textAchievement)
+	achievements, err := q.ListAchievements(appContext)
+	if err != nil {
+		return err
+	}
+	for _, achievement := range achievements {
+		// habit wide achievements are keyed by 0 as in the achievements index
+		achievementsByHabitID[achievement.HabitID.Int64] = append(achievementsByHabitID[achievement.HabitID.Int64], textAchievement{
+			Code:       achievement.Code,
+			UnlockedAt: achievement.UnlockedAt,
+		})
+	}
+
+	ledgers := make(map[int][]string)
+	for _, habit := range habits {
+		tags, err := q.ListTagsForHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		reminders, err := q.ListRemindersForHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		data, err := marshalYAML(textHabit{
+			Name:                 habit.Name,
+			Type:                 habit.HabitType,
+			Description:          habit.Description.String,
+			CreatedAt:            habit.CreatedAt,
+			CostPerOccurrence:    habit.CostPerOccurrence.Float64,
+			MinutesPerOccurrence: habit.MinutesPerOccurrence.Int64,
+			Tags:                 tags,
+			Reminders:            reminders,
+			Goals:                goalsByHabitID[habit.ID],
+			Achievements:         achievementsByHabitID[habit.ID],
+		})
+		if err != nil {
+			return err
+		}
+		files[filepath.Join(textHabitsDir, habit.Slug+textHabitExt)] = data
+
+		streaks, err := q.ListStreaksForHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		slipups, err := q.ListSlipupsForHabit(appContext, habit.ID)
+		if err != nil {
+			return err
+		}
+		slipupCounts := make(map[string]int64)
+		for _, slipup := range slipups {
+			slipupCounts[slipup.SlipDate.Format(textDayLayout)] = slipup.Count
+		}
+		for _, day := range LoggedDays(habit, streaks) {
+			line := day.Format(textDayLayout) + " " + habit.Slug
+			if count := slipupCounts[day.Format(textDayLayout)]; count > 1 {
+				line += fmt.Sprintf(" x%d", count)
+			}
+			ledgers[day.Year()] = append(ledgers[day.Year()], line)
+		}
+	}
+	for year, lines := range ledgers {
+		sort.Strings(lines)
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "# %d ledger, one line per logged day (slip-up days for quit habits)\n", year)
+		for _, line := range lines {
+			buf.WriteString(line + "\n")
+		}
+		files[fmt.Sprintf("%d%s", year, textLedgerExt)] = buf.Bytes()
+	}
+
+	routineHabits, err := q.ListRoutineHabits(appContext)
+	if err != nil {
+		return err
+	}
+	routines := make([]textRoutine, 0)
+	for _, row := range routineHabits {
+		if len(routines) == 0 || routines[len(routines)-1].Name != row.Name {
+			routines = append(routines, textRoutine{Name: row.Name})
+		}
+		last := &routines[len(routines)-1]
+		last.Habits = append(last.Habits, row.HabitName)
+	}
+	if len(routines) > 0 {
+		if files[textRoutinesFile], err = marshalYAML(routines); err != nil {
+			return err
+		}
+	}
+	if len(achievementsByHabitID[0]) > 0 {
+		if files[textAchievementsFile], err = marshalYAML(achievementsByHabitID[0]); err != nil {
+			return err
+		}
+	}
+
+	syncOps, err := q.ListSyncOps(appContext)
+	if err != nil {
+		return err
+	}
+	if len(syncOps) > 0 {
+		var buf bytes.Buffer
+		for _, op := range syncOps {
+			line, err := json.Marshal(textSyncOp{
+				Device:  op.Device,
+				Seq:     op.Seq,
+				Key:     op.Key,
+				OpTime:  op.OpTime,
+				Payload: json.RawMessage(op.Payload),
+			})
+			if err != nil {
+				return err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		files[textSyncOpsFile] = buf.Bytes()
+	}
+	return writeTextFiles(dir, files, known)
+}
+
+func marshalYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeTextFiles brings dir in line with files. known holds the files as this process loaded or last wrote
+// them and is kept up to date: only files whose content differs from known are written, and only known files
+// are removed. A file someone else changed in the meantime, e.g. by a git pull, is never overwritten or
+// removed, nothing is written then and the error names the files.
+func writeTextFiles(dir string, files, known map[string][]byte) error {
+	if err := os.MkdirAll(filepath.Join(dir, textHabitsDir), 0700); err != nil {
+		return err
+	}
+	writes := make([]string, 0)
+	removals := make([]string, 0)
+	conflicts := make([]string, 0)
+	for name, data := range files {
+		loaded, isKnown := known[name]
+		if isKnown && bytes.Equal(loaded, data) {
+			continue
+		}
+		existing, err := os.ReadFile(filepath.Join(dir, name))
+		switch {
+		case err == nil && bytes.Equal(existing, data):
+			known[name] = data
+		case err == nil && isKnown && bytes.Equal(existing, loaded):
+			writes = append(writes, name)
+		case err == nil:
+			conflicts = append(conflicts, name)
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		case isKnown:
+			// removed by someone else
+			conflicts = append(conflicts, name)
+		default:
+			writes = append(writes, name)
+		}
+	}
+	for name, loaded := range known {
+		if _, ok := files[name]; ok {
+			continue
+		}
+		existing, err := os.ReadFile(filepath.Join(dir, name))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			delete(known, name)
+		case err != nil:
+			return err
+		case bytes.Equal(existing, loaded):
+			removals = append(removals, name)
+		default:
+			conflicts = append(conflicts, name)
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("changed in %s since streakr loaded them, not overwriting: %s", dir, strings.Join(conflicts, ", "))
+	}
+
+	for _, name := range writes {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path+".tmp", files[name], 0600); err != nil {
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+		known[name] = files[name]
+	}
+	for _, name := range removals {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		delete(known, name)
+	}
+	return nil
+}
+
+// readTextFiles returns the content of the files in dir that the text backend manages, by their name relative to dir.
+// textFilePaths returns the paths of the managed files in dir.
+func textFilePaths(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, textHabitsDir, "*"+textHabitExt))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if textLedgerName.MatchString(name) || name == textRoutinesFile || name == textAchievementsFile || name == textSyncOpsFile {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	return paths, nil
+}
+
+// textModTime returns the latest modification time of the managed files in dir, and of dir and its
+// habits dir which change when a file is removed.
+func textModTime(dir string) (int64, error) {
+	paths, err := textFilePaths(dir)
+	if err != nil {
+		return 0, err
+	}
+	return latestModTime(append(paths, dir, filepath.Join(dir, textHabitsDir))...)
+}
+
+func readTextFiles(dir string) (map[string][]byte, error) {
+	paths, err := textFilePaths(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		if files[name], err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// loadText fills the empty db from the files in dir.
+func loadText(appContext context.Context, dir string) error {
+	tx, err := GetDB().BeginTx(appContext, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	q := GetQueries().WithTx(tx)
+
+	paths, err := filepath.Glob(filepath.Join(dir, textHabitsDir, "*"+textHabitExt))
+	if err != nil {
+		return err
+	}
+	textHabits := make(map[string]textHabit)
+	slugs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var habit textHabit
+		if err = yaml.Unmarshal(data, &habit); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		slug := strings.TrimSuffix(filepath.Base(path), textHabitExt)
+		textHabits[slug] = habit
+		slugs = append(slugs, slug)
+	}
+	// keep the creation order of habits, lists follow it
+	sort.Slice(slugs, func(i, j int) bool {
+		a, b := textHabits[slugs[i]], textHabits[slugs[j]]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return slugs[i] < slugs[j]
+	})
+
+	habitsBySlug := make(map[string]generated.Habit)
+	for _, slug := range slugs {
+		habit, err := loadTextHabit(appContext, q, slug, textHabits[slug])
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Join(dir, textHabitsDir, slug+textHabitExt), err)
+		}
+		habitsBySlug[slug] = habit
+	}
+
+	if err = loadTextLedgers(appContext, q, dir, habitsBySlug); err != nil {
+		return err
+	}
+
+	var routines []textRoutine
+	if err = readYAMLFile(filepath.Join(dir, textRoutinesFile), &routines); err != nil {
+		return err
+	}
+	for _, routine := range routines {
+		routineID, err := q.CreateRoutine(appContext, routine.Name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", textRoutinesFile, err)
+		}
+		for i, habitName := range routine.Habits {
+			err = q.AddRoutineHabit(appContext, generated.AddRoutineHabitParams{
+				RoutineID: routineID,
+				HabitName: habitName,
+				Position:  int64(i),
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", textRoutinesFile, err)
+			}
+		}
+	}
+
+	var achievements []textAchievement
+	if err = readYAMLFile(filepath.Join(dir, textAchievementsFile), &achievements); err != nil {
+		return err
+	}
+	for _, achievement := range achievements {
+		err = q.RestoreAchievement(appContext, generated.RestoreAchievementParams{
+			Code:       achievement.Code,
+			UnlockedAt: achievement.UnlockedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", textAchievementsFile, err)
+		}
+	}
+
+	if err = loadTextSyncOps(appContext, q, filepath.Join(dir, textSyncOpsFile)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func loadTextHabit(appContext context.Context, q *generated.Queries, slug string, habit textHabit) (generated.Habit, error) {
+	id, err := q.AddHabitWithCreatedAt(appContext, generated.AddHabitWithCreatedAtParams{
+		Name:        habit.Name,
+		Slug:        slug,
+		Description: sql.NullString{String: habit.Description, Valid: habit.Description != ""},
+		HabitType:   habit.Type,
+		CreatedAt:   habit.CreatedAt,
+	})
+	if err != nil {
+		return generated.Habit{}, err
+	}
+	if habit.CostPerOccurrence > 0 || habit.MinutesPerOccurrence > 0 {
+		err = q.UpdateHabitCosts(appContext, generated.UpdateHabitCostsParams{
+			ID:                   id,
+			CostPerOccurrence:    sql.NullFloat64{Float64: habit.CostPerOccurrence, Valid: habit.CostPerOccurrence > 0},
+			MinutesPerOccurrence: sql.NullInt64{Int64: habit.MinutesPerOccurrence, Valid: habit.MinutesPerOccurrence > 0},
+		})
+		if err != nil {
+			return generated.Habit{}, err
+		}
+	}
+	for _, tag := range habit.Tags {
+		tagID, err := q.UpsertTag(appContext, tag)
+		if err != nil {
+			return generated.Habit{}, err
+		}
+		if err = q.AddHabitTag(appContext, generated.AddHabitTagParams{HabitID: id, TagID: tagID}); err != nil {
+			return generated.Habit{}, err
+		}
+	}
+	for _, reminder := range habit.Reminders {
+		if err = q.AddReminder(appContext, generated.AddReminderParams{HabitID: id, RemindAt: reminder}); err != nil {
+			return generated.Habit{}, err
+		}
+	}
+	for _, goal := range habit.Goals {
+		start, err := parseTextDay(goal.Start)
+		if err != nil {
+			return generated.Habit{}, err
+		}
+		deadline, err := parseTextDay(goal.Deadline)
+		if err != nil {
+			return generated.Habit{}, err
+		}
+		err = q.RestoreGoal(appContext, generated.RestoreGoalParams{
+			ID:        goal.ID,
+			HabitID:   id,
+			Kind:      goal.Kind,
+			Target:    goal.Target,
+			StartDate: start,
+			Deadline:  deadline,
+			CreatedAt: goal.CreatedAt,
+		})
+		if err != nil {
+			return generated.Habit{}, err
+		}
+	}
+	for _, achievement := range habit.Achievements {
+		err = q.RestoreAchievement(appContext, generated.RestoreAchievementParams{
+			Code:       achievement.Code,
+			HabitID:    sql.NullInt64{Int64: id, Valid: true},
+			UnlockedAt: achievement.UnlockedAt,
+		})
+		if err != nil {
+			return generated.Habit{}, err
+		}
+	}
+	return q.GetHabit(appContext, id)
+}
+
+// loadTextLedgers turns the logged days of every ledger back into streaks and slip-up counts.
+func loadTextLedgers(appContext context.Context, q *generated.Queries, dir string, habitsBySlug map[string]generated.Habit) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	loggedDays := make(map[string][]time.Time)
+	slipupCounts := make(map[string]map[time.Time]int64)
+	for _, entry := range entries {
+		if !textLedgerName.MatchString(entry.Name()) {
+			continue
+		}
+		file, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(file)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			day, slug, count, err := parseLedgerLine(line)
+			if err == nil {
+				if _, ok := habitsBySlug[slug]; !ok {
+					err = fmt.Errorf("unknown habit %s", slug)
+				}
+			}
+			if err != nil {
+				file.Close()
+				return fmt.Errorf("%s line %d: %w", entry.Name(), lineNumber, err)
+			}
+			loggedDays[slug] = append(loggedDays[slug], day)
+			if count > 1 {
+				if slipupCounts[slug] == nil {
+					slipupCounts[slug] = make(map[time.Time]int64)
+				}
+				slipupCounts[slug][day] = count
+			}
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	for slug, days := range loggedDays {
+		habit := habitsBySlug[slug]
+		sort.Slice(days, func(i, j int) bool {
+			return days[i].Before(days[j])
+		})
+		days = compactDays(days)
+		for _, streak := range BuildStreaks(habit, days) {
+			if _, err = q.AddStreak(appContext, streak); err != nil {
+				return err
+			}
+		}
+		if habit.HabitType != HabitTypeQuit {
+			continue
+		}
+		for day, count := range slipupCounts[slug] {
+			err = q.AddSlipupCount(appContext, generated.AddSlipupCountParams{HabitID: habit.ID, SlipDate: day, Count: count})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseLedgerLine parses "2026-10-19 running" or "2026-10-19 smoking x3".
+func parseLedgerLine(line string) (time.Time, string, int64, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return time.Time{}, "", 0, fmt.Errorf("expected \"YYYY-MM-DD habit [xN]\", got %q", line)
+	}
+	day, err := parseTextDay(fields[0])
+	if err != nil {
+		return time.Time{}, "", 0, err
+	}
+	count := int64(1)
+	if len(fields) == 3 {
+		count, err = strconv.ParseInt(strings.TrimPrefix(fields[2], "x"), 10, 64)
+		if err != nil || !strings.HasPrefix(fields[2], "x") || count < 1 {
+			return time.Time{}, "", 0, fmt.Errorf("invalid count %q, expected e.g. x3", fields[2])
+		}
+	}
+	return day, fields[1], count, nil
+}
+
+// parseTextDay parses a YYYY-MM-DD day to noon local time, the way days are stored.
+func parseTextDay(value string) (time.Time, error) {
+	day, err := time.ParseInLocation(textDayLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid day %q, expected YYYY-MM-DD", value)
+	}
+	return util.GetNoonOf(day), nil
+}
+
+// compactDays drops repeated days from sorted days.
+func compactDays(days []time.Time) []time.Time {
+	compacted := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		if len(compacted) == 0 || !util.IsSameDate(compacted[len(compacted)-1], day) {
+			compacted = append(compacted, day)
+		}
+	}
+	return compacted
+}
+
+func readYAMLFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func loadTextSyncOps(appContext context.Context, q *generated.Queries, path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var op textSyncOp
+		if err = json.Unmarshal(line, &op); err != nil {
+			return fmt.Errorf("%s line %d: %w", path, i+1, err)
+		}
+		err = q.AddSyncOp(appContext, generated.AddSyncOpParams{
+			Device:  op.Device,
+			Seq:     op.Seq,
+			Key:     op.Key,
+			OpTime:  op.OpTime,
+			Payload: string(op.Payload),
+		})
+		if err != nil {
+			return fmt.Errorf("%s line %d: %w", path, i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestText_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, openMemoryDB(nil))
+	t.Cleanup(func() { db.Close() })
+	q := GetQueries()
+
+	day := func(value string) time.Time {
+		parsed, err := parseTextDay(value)
+		require.NoError(t, err)
+		return parsed
+	}
+	createdAt := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	runningID, err := q.AddHabitWithCreatedAt(ctx, generated.AddHabitWithCreatedAtParams{
+		Name: "Morning Run", Slug: "morning-run", HabitType: HabitTypeImprove, CreatedAt: createdAt,
+		Description: sql.NullString{String: "5k", Valid: true},
+	})
+	require.NoError(t, err)
+	smokingID, err := q.AddHabitWithCreatedAt(ctx, generated.AddHabitWithCreatedAtParams{
+		Name: "smoking", Slug: "smoking", HabitType: HabitTypeQuit, CreatedAt: createdAt.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	_, err = q.AddStreak(ctx, generated.AddStreakParams{HabitID: runningID, StreakStart: day("2025-12-31"), StreakEnd: day("2026-01-02")})
+	require.NoError(t, err)
+	// a quit habit's first streak starts the day after its creation and ends with the slip-up
+	_, err = q.AddStreak(ctx, generated.AddStreakParams{HabitID: smokingID, StreakStart: day("2026-01-02"), StreakEnd: day("2026-01-03")})
+	require.NoError(t, err)
+	require.NoError(t, q.AddSlipupCount(ctx, generated.AddSlipupCountParams{HabitID: smokingID, SlipDate: day("2026-01-03"), Count: 3}))
+	tagID, err := q.UpsertTag(ctx, "health")
+	require.NoError(t, err)
+	require.NoError(t, q.AddHabitTag(ctx, generated.AddHabitTagParams{HabitID: runningID, TagID: tagID}))
+
+	dir := t.TempDir()
+	known := make(map[string][]byte)
+	require.NoError(t, dumpText(ctx, q, dir, known))
+	ledger, err := os.ReadFile(filepath.Join(dir, "2026.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(ledger), "2026-01-01 morning-run\n2026-01-02 morning-run\n2026-01-03 smoking x3\n")
+	ledger, err = os.ReadFile(filepath.Join(dir, "2025.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(ledger), "2025-12-31 morning-run\n")
+
+	db.Close()
+	require.NoError(t, openMemoryDB(nil))
+	require.NoError(t, loadText(ctx, dir))
+	q = GetQueries()
+	habits, err := q.ListHabits(ctx)
+	require.NoError(t, err)
+	require.Len(t, habits, 2)
+	assert.Equal(t, "morning-run", habits[0].Slug)
+	assert.Equal(t, "5k", habits[0].Description.String)
+	streaks, err := q.ListStreaksForHabit(ctx, habits[0].ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+	assert.True(t, util.IsSameDate(day("2025-12-31"), streaks[0].StreakStart))
+	assert.True(t, util.IsSameDate(day("2026-01-02"), streaks[0].StreakEnd))
+	assert.Equal(t, "smoking", habits[1].Slug)
+	streaks, err = q.ListStreaksForHabit(ctx, habits[1].ID)
+	require.NoError(t, err)
+	require.Len(t, streaks, 1)
+	assert.True(t, util.IsSameDate(day("2026-01-02"), streaks[0].StreakStart))
+	assert.True(t, util.IsSameDate(day("2026-01-03"), streaks[0].StreakEnd))
+	slipups, err := q.ListSlipupsForHabit(ctx, habits[1].ID)
+	require.NoError(t, err)
+	require.Len(t, slipups, 1)
+	assert.True(t, util.IsSameDate(day("2026-01-03"), slipups[0].SlipDate))
+	assert.Equal(t, int64(3), slipups[0].Count)
+
+	// dumping the loaded db again gives the same files
+	again := t.TempDir()
+	require.NoError(t, dumpText(ctx, q, again, make(map[string][]byte)))
+	for _, name := range []string{"2025.log", "2026.log", "habits/morning-run.yaml", "habits/smoking.yaml"} {
+		want, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(again, name))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got), name)
+	}
+
+	// a deleted habit takes its file and empty ledgers with it
+	require.NoError(t, q.DeleteHabit(ctx, habits[0].ID))
+	require.NoError(t, dumpText(ctx, q, dir, known))
+	assert.NoFileExists(t, filepath.Join(dir, "habits", "morning-run.yaml"))
+	assert.NoFileExists(t, filepath.Join(dir, "2025.log"))
+}
+
+func TestWriteTextFiles_OnlyTouchesOwnChanges(t *testing.T) {
+	dir := t.TempDir()
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	known := make(map[string][]byte)
+	require.NoError(t, writeTextFiles(dir, map[string][]byte{
+		"2026.log":            []byte("2026-01-01 running\n"),
+		"habits/running.yaml": []byte("name: running\n"),
+	}, known))
+
+	// files arriving from elsewhere, e.g. a git pull, are neither overwritten nor removed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "habits", "reading.yaml"), []byte("name: reading\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "habits", "running.yaml"), []byte("name: running\ntags: [health]\n"), 0600))
+	require.NoError(t, writeTextFiles(dir, map[string][]byte{
+		"2026.log":            []byte("2026-01-01 running\n2026-01-02 running\n"),
+		"habits/running.yaml": []byte("name: running\n"),
+	}, known))
+	assert.Equal(t, "2026-01-01 running\n2026-01-02 running\n", read("2026.log"))
+	assert.Equal(t, "name: running\ntags: [health]\n", read("habits/running.yaml"))
+	assert.Equal(t, "name: reading\n", read("habits/reading.yaml"))
+
+	// changing a file someone else changed is refused, and nothing is written
+	err := writeTextFiles(dir, map[string][]byte{
+		"2026.log":            []byte("2026-01-01 running\n"),
+		"habits/running.yaml": []byte("name: running\ndescription: 5k\n"),
+	}, known)
+	assert.ErrorContains(t, err, "habits/running.yaml")
+	assert.Equal(t, "2026-01-01 running\n2026-01-02 running\n", read("2026.log"))
+
+	// known files are removed once they are gone
+	delete(known, "habits/running.yaml")
+	require.NoError(t, writeTextFiles(dir, map[string][]byte{}, known))
+	assert.NoFileExists(t, filepath.Join(dir, "2026.log"))
+	assert.FileExists(t, filepath.Join(dir, "habits", "running.yaml"))
+	assert.FileExists(t, filepath.Join(dir, "habits", "reading.yaml"))
+}
+
+func TestText_ParseLedgerLine(t *testing.T) {
+	_, slug, count, err := parseLedgerLine("2026-10-19 smoking x3")
+	require.NoError(t, err)
+	assert.Equal(t, "smoking", slug)
+	assert.Equal(t, int64(3), count)
+
+	for _, line := range []string{"2026-10-19", "19-10-2026 smoking", "2026-10-19 smoking 3", "2026-10-19 smoking x0", "a b c d"} {
+		_, _, _, err = parseLedgerLine(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestBootstrapTextStore_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, writeTextFiles(dir, map[string][]byte{
+		"2026.log":            []byte("2026-01-01 running\n"),
+		"habits/running.yaml": []byte("name: running\ntype: improve\ncreated_at: 2026-01-01T08:00:00Z\n"),
+	}, make(map[string][]byte)))
+	readOnly = true
+	t.Cleanup(func() {
+		readOnly = false
+		textDir, textFiles = "", nil
+		db.Close()
+	})
+
+	// the files are loaded before writes are refused
+	require.NoError(t, bootstrapTextStore(dir))
+	habits, err := GetQueries().ListHabits(ctx)
+	require.NoError(t, err)
+	require.Len(t, habits, 1)
+	assert.Equal(t, "running", habits[0].Slug)
+	_, err = GetQueries().AddHabit(ctx, generated.AddHabitParams{Name: "reading", Slug: "reading", HabitType: HabitTypeImprove})
+	assert.Error(t, err)
+}
+
+func TestReload_Text(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, writeTextFiles(dir, map[string][]byte{
+		"habits/running.yaml": []byte("name: running\ntype: improve\ncreated_at: 2026-01-01T08:00:00Z\n"),
+	}, make(map[string][]byte)))
+	readOnly = true
+	t.Cleanup(func() {
+		readOnly, inMemory = false, false
+		textDir, textFiles = "", nil
+		db.Close()
+	})
+	require.NoError(t, bootstrapTextStore(dir))
+
+	// unchanged files keep the loaded db
+	loaded := GetDB()
+	require.NoError(t, Reload())
+	assert.Same(t, loaded, GetDB())
+
+	// a habit added by another process shows up, and the reloaded db is still read only
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "habits", "reading.yaml"), []byte("name: reading\ntype: improve\ncreated_at: 2026-01-02T08:00:00Z\n"), 0600))
+	require.NoError(t, Reload())
+	habits, err := GetQueries().ListHabits(ctx)
+	require.NoError(t, err)
+	assert.Len(t, habits, 2)
+	_, err = GetQueries().AddHabit(ctx, generated.AddHabitParams{Name: "writing", Slug: "writing", HabitType: HabitTypeImprove})
+	assert.Error(t, err)
+}
@@ -1,34 +1,82 @@
 package streakr
 
 import (
+	"errors"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/events"
 	"github.com/Atharva21/streakr/internal/log"
+	"github.com/Atharva21/streakr/internal/shutdown"
 	"github.com/Atharva21/streakr/internal/store"
 	"github.com/Atharva21/streakr/internal/util"
 )
 
-var bootstrapOnce sync.Once
+// webhookShutdownWait bounds how long exiting waits for webhooks still being delivered.
+const webhookShutdownWait = 10 * time.Second
 
-func bootsrapStreakr() {
-	bootstrapOnce.Do(func() {
-		// bootstrap app config
-		config.BootstrapConfig()
-		appConfig := config.GetStreakrConfig()
-
-		// bootstrap logger
-		log.BootsrapLogger(filepath.Join(appConfig.LogFileDir, appConfig.LogFileName))
+var (
+	bootstrapOnce sync.Once
+	bootstrapErr  error
+)
 
-		// bootsrap util
-		util.BootstrapUtil(filepath.Join(appConfig.LogFileDir, appConfig.LogFileName))
+// Bootstrap prepares everything commands working with habits rely on, creating the config dirs
+// and opening the store, for reading only if readOnly is set. Only the first call does anything,
+// later ones return its error.
+func Bootstrap(options config.Options, readOnly bool) error {
+	bootstrapOnce.Do(func() {
+		bootstrapErr = bootstrapStreakr(options, readOnly, func() ([]byte, error) {
+			return config.GetPassphrase(false)
+		})
+	})
+	return bootstrapErr
+}
 
-		// bootstrap store
-		store.BootstrapStore(filepath.Join(appConfig.DataDir, appConfig.StoreName))
+// BootstrapForCompletion is Bootstrap with a read only store for shell completion. It never prompts for
+// the passphrase of an encrypted db, shells hide the prompt and would wait for it forever.
+func BootstrapForCompletion(options config.Options) error {
+	bootstrapOnce.Do(func() {
+		bootstrapErr = bootstrapStreakr(options, true, config.GetPassphraseWithoutPrompt)
 	})
+	return bootstrapErr
 }
 
-func init() {
-	bootsrapStreakr()
+func bootstrapStreakr(options config.Options, readOnly bool, passphrase store.PassphraseFunc) error {
+	// bootstrap app config
+	if err := config.BootstrapConfig(options); err != nil {
+		return err
+	}
+	appConfig := config.GetStreakrConfig()
+
+	// bootstrap logger
+	log.BootsrapLogger(filepath.Join(appConfig.LogFileDir, appConfig.LogFileName))
+
+	// bootsrap util
+	util.BootstrapUtil(filepath.Join(appConfig.LogFileDir, appConfig.LogFileName))
+
+	// bootstrap store
+	err := store.BootstrapStore(filepath.Join(appConfig.DataDir, appConfig.StoreName), appConfig.TextDir, readOnly, passphrase)
+	if err != nil {
+		return err
+	}
+
+	// bootstrap event handlers
+	events.RegisterHandler(events.NewScriptHandler(appConfig.HooksDir))
+	for _, webhook := range appConfig.Settings.Webhooks {
+		events.RegisterHandler(events.Async(events.NewWebhookHandler(events.WebhookConfig{
+			URL:     webhook.URL,
+			Events:  webhook.Events,
+			Timeout: time.Duration(webhook.TimeoutSeconds) * time.Second,
+			Retries: webhook.Retries,
+		})))
+	}
+	shutdown.RegisterCleanupHook(func() error {
+		if !events.Wait(webhookShutdownWait) {
+			return errors.New("gave up waiting for webhook deliveries")
+		}
+		return nil
+	})
+	return nil
 }
@@ -1,9 +1,62 @@
 package streakrerror
 
+import "errors"
+
+// Kind classifies errors for exit codes and http statuses.
+type Kind int
+
+const (
+	// KindUnspecified is counted as KindValidation for errors with a TerminalMsg and KindInternal otherwise.
+	KindUnspecified Kind = iota
+	KindInternal
+	KindValidation
+	KindNotFound
+	KindAlreadyExists
+	KindLocked
+	KindMigration
+	// KindHabitsRemaining isn't a failure, status reports habits still to be logged today with it.
+	KindHabitsRemaining
+)
+
+var kindNames = map[Kind]string{
+	KindUnspecified:     "unspecified",
+	KindInternal:        "internal",
+	KindValidation:      "validation",
+	KindNotFound:        "not_found",
+	KindAlreadyExists:   "already_exists",
+	KindLocked:          "locked",
+	KindMigration:       "migration",
+	KindHabitsRemaining: "habits_remaining",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// ExitCode is the status streakr exits with for an error of this kind.
+func (k Kind) ExitCode() int {
+	switch k {
+	case KindValidation:
+		return 2
+	case KindNotFound:
+		return 3
+	case KindAlreadyExists:
+		return 4
+	case KindLocked:
+		return 5
+	case KindMigration:
+		return 6
+	case KindHabitsRemaining:
+		return 7
+	}
+	return 1
+}
+
 type StreakrError struct {
 	Err         error
 	TerminalMsg string
 	ShowUsage   bool
+	Kind        Kind
 }
 
 func (e *StreakrError) Error() string {
@@ -19,3 +72,25 @@ func (e *StreakrError) Error() string {
 func (e *StreakrError) Unwrap() error {
 	return e.Err
 }
+
+// KindOf is the kind of the outermost StreakrError in err's chain with a kind, or of the outermost one
+// if none has a kind set. Errors without a StreakrError are KindInternal.
+func KindOf(err error) Kind {
+	var outermost *StreakrError
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		streakrErr, ok := current.(*StreakrError)
+		if !ok {
+			continue
+		}
+		if streakrErr.Kind != KindUnspecified {
+			return streakrErr.Kind
+		}
+		if outermost == nil {
+			outermost = streakrErr
+		}
+	}
+	if outermost != nil && outermost.TerminalMsg != "" {
+		return KindValidation
+	}
+	return KindInternal
+}
@@ -0,0 +1,30 @@
+package streakrerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, KindInternal, KindOf(errors.New("boom")))
+	assert.Equal(t, KindInternal, KindOf(&StreakrError{Err: errors.New("boom")}))
+	assert.Equal(t, KindValidation, KindOf(&StreakrError{TerminalMsg: "habit name cannot be empty"}))
+	assert.Equal(t, KindNotFound, KindOf(fmt.Errorf("log: %w", &StreakrError{TerminalMsg: "No habit with name x", Kind: KindNotFound})))
+	// a kind further down the chain wins over an unspecified one
+	migration := &StreakrError{Kind: KindMigration, Err: errors.New("dirty database version 3")}
+	assert.Equal(t, KindMigration, KindOf(&StreakrError{TerminalMsg: "could not start streakr", Err: migration}))
+}
+
+func TestKind_ExitCode(t *testing.T) {
+	codes := map[int]Kind{}
+	for _, kind := range []Kind{KindInternal, KindValidation, KindNotFound, KindAlreadyExists, KindLocked, KindMigration, KindHabitsRemaining} {
+		assert.NotContains(t, codes, kind.ExitCode(), kind.String())
+		assert.NotZero(t, kind.ExitCode())
+		codes[kind.ExitCode()] = kind
+	}
+	assert.Equal(t, 1, KindUnspecified.ExitCode())
+	assert.Equal(t, "not_found", KindNotFound.String())
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Atharva21/streakr/internal/service"
 	"github.com/Atharva21/streakr/internal/store"
 	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
 	"github.com/Atharva21/streakr/internal/util"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
@@ -28,13 +31,78 @@ type StatsModel struct {
 	HasPreviousNbr      bool
 	HasNxtNbr           bool
 	ParentTable         *table.Model
+	StrengthHistory     []float64
+	Goals               []types.GoalProgress
+	Savings             *types.QuitHabitSavings // only set for quit habits
+}
+
+// goalProgressBarWidth keeps goal bars within the width of the calendar.
+const goalProgressBarWidth = 20
+
+// slipupTrendWeeks is the number of past weeks shown in the slip-up trend of quit habits.
+const slipupTrendWeeks = 8
+
+// strengthHistoryDays is the number of past days shown in the strength sparkline.
+const strengthHistoryDays = 90
+
+var sparklineTicks = []rune("▁▂▃▄▅▆▇█")
+
+// renderSparkline maps values in the 0-100 range onto block characters.
+func renderSparkline(values []float64) string {
+	sparkline := make([]rune, len(values))
+	for i, val := range values {
+		idx := int(val / 100 * float64(len(sparklineTicks)-1))
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(sparklineTicks) {
+			idx = len(sparklineTicks) - 1
+		}
+		sparkline[i] = sparklineTicks[idx]
+	}
+	return string(sparkline)
+}
+
+// renderSavings shows what staying clean saved and the weekly slip-up trend of a quit habit.
+func renderSavings(habit generated.Habit, savings *types.QuitHabitSavings, savedStyle, slipupStyle, helpStyle lipgloss.Style) string {
+	view := ""
+	saved := make([]string, 0, 2)
+	if habit.CostPerOccurrence.Valid {
+		saved = append(saved, fmt.Sprintf("%.2f", savings.MoneySaved))
+	}
+	if habit.MinutesPerOccurrence.Valid {
+		saved = append(saved, FormatMinutes(savings.MinutesSaved))
+	}
+	if len(saved) > 0 {
+		view += "Saved: " + savedStyle.Render(strings.Join(saved, " · "))
+		view += fmt.Sprintf(" in %d clean days\n", savings.CleanDays)
+	}
+	if len(savings.SlipupsPerWeek) == 0 {
+		return view
+	}
+	var maxSlipups int64
+	counts := make([]string, 0, len(savings.SlipupsPerWeek))
+	for _, count := range savings.SlipupsPerWeek {
+		maxSlipups = max(maxSlipups, count)
+		counts = append(counts, fmt.Sprintf("%d", count))
+	}
+	trend := make([]float64, len(savings.SlipupsPerWeek))
+	for i, count := range savings.SlipupsPerWeek {
+		if maxSlipups > 0 {
+			trend[i] = float64(count) / float64(maxSlipups) * 100
+		}
+	}
+	view += fmt.Sprintf("Slip-ups per week: %s\n", strings.Join(counts, " "))
+	view += slipupStyle.Render(renderSparkline(trend)) + "\n"
+	view += helpStyle.Render(fmt.Sprintf("last %d weeks, %d slip-ups in total", len(savings.SlipupsPerWeek), savings.TotalSlipups)) + "\n"
+	return view
 }
 
 func (m StatsModel) Init() tea.Cmd {
 	return func() tea.Msg {
 		rangedStats, err := service.GetHabitStatsForRange(
 			m.Ctx,
-			m.Habit.Name,
+			m.Habit.Slug,
 			m.FirstDayOfSetMonth,
 			m.FirstDayOfSetMonth.AddDate(0, 1, -1),
 		)
@@ -42,6 +110,24 @@ func (m StatsModel) Init() tea.Cmd {
 			slog.Error("error in getting ranged habit stats in calview", "err", err.Error())
 			return viewErrorMsg{err: err}
 		}
+		strengthHistory, err := service.GetHabitStrengthHistory(m.Ctx, rangedStats.Habit, strengthHistoryDays)
+		if err != nil {
+			slog.Error("error in getting habit strength history in calview", "err", err.Error())
+			return viewErrorMsg{err: err}
+		}
+		goals, err := service.GetGoalProgressForHabit(m.Ctx, rangedStats.Habit)
+		if err != nil {
+			slog.Error("error in getting goal progress in calview", "err", err.Error())
+			return viewErrorMsg{err: err}
+		}
+		var savings *types.QuitHabitSavings
+		if rangedStats.Habit.HabitType == store.HabitTypeQuit {
+			savings, err = service.GetQuitHabitSavings(m.Ctx, rangedStats.Habit, slipupTrendWeeks)
+			if err != nil {
+				slog.Error("error in getting quit habit savings in calview", "err", err.Error())
+				return viewErrorMsg{err: err}
+			}
+		}
 		return StatsModel{
 			Ctx:                 m.Ctx,
 			FirstDayOfSetMonth:  m.FirstDayOfSetMonth,
@@ -54,6 +140,9 @@ func (m StatsModel) Init() tea.Cmd {
 			TotalStreaksInMonth: rangedStats.TotalStreakDaysInRange,
 			TotalMissesInMonth:  rangedStats.TotalMissesInRange,
 			ParentTable:         m.ParentTable,
+			StrengthHistory:     strengthHistory,
+			Goals:               goals,
+			Savings:             savings,
 		}
 	}
 }
@@ -68,26 +157,11 @@ const (
 func getNeighbourMonthStatsCmd(m StatsModel, nbrType neighborMonth) tea.Cmd {
 	firstDayOfNbrMonth := m.FirstDayOfSetMonth.AddDate(0, int(nbrType), 0)
 	lastDayOfNbrMonth := firstDayOfNbrMonth.AddDate(0, 1, -1)
-	habitStart := m.Habit.CreatedAt
-	today := m.Today
-	if firstDayOfNbrMonth.Year() < habitStart.Year() {
+	if !util.RangesOverlap(firstDayOfNbrMonth, lastDayOfNbrMonth, m.Habit.CreatedAt, m.Today) {
 		return nil
 	}
-	if firstDayOfNbrMonth.Year() == habitStart.Year() {
-		if firstDayOfNbrMonth.Month() < habitStart.Month() {
-			return nil
-		}
-	}
-	if firstDayOfNbrMonth.Year() > today.Year() {
-		return nil
-	}
-	if firstDayOfNbrMonth.Year() == today.Year() {
-		if firstDayOfNbrMonth.Month() > today.Month() {
-			return nil
-		}
-	}
 	return func() tea.Msg {
-		rangedStats, err := service.GetHabitStatsForRange(m.Ctx, m.Habit.Name, firstDayOfNbrMonth, lastDayOfNbrMonth)
+		rangedStats, err := service.GetHabitStatsForRange(m.Ctx, m.Habit.Slug, firstDayOfNbrMonth, lastDayOfNbrMonth)
 		if err != nil {
 			slog.Error("error in getting ranged habit stats in calview", "err", err.Error())
 			return viewErrorMsg{
@@ -110,8 +184,11 @@ func getNeighbourMonthStatsCmd(m StatsModel, nbrType neighborMonth) tea.Cmd {
 			Today:               m.Today,
 			ExitError:           nil,
 			HasPreviousNbr:      util.AtLeastOneMonthOlder(m.Habit.CreatedAt, firstDayOfNbrMonth),
-			HasNxtNbr:           util.AtLeastOneMonthOlder(firstDayOfNbrMonth, today),
+			HasNxtNbr:           util.AtLeastOneMonthOlder(firstDayOfNbrMonth, m.Today),
 			ParentTable:         m.ParentTable,
+			StrengthHistory:     m.StrengthHistory,
+			Goals:               m.Goals,
+			Savings:             m.Savings,
 		}
 		return sm
 	}
@@ -145,70 +222,120 @@ func (m StatsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m StatsModel) View() string {
-	monthTitleColor := lipgloss.Color("#5d8addff")
-	weekDayHeaderColor := lipgloss.Color("#5d8addff")
-	todaysDateBGColor := lipgloss.Color("#5d8addff")
-	weekdayStyle := lipgloss.
-		NewStyle().
-		Align(lipgloss.Left).
-		Foreground(weekDayHeaderColor)
-	streakColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#25a425ff"))
-	missColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#c25252ff"))
+	theme := getTheme()
+	streakColor := theme.DoneStyle()
+	missColor := theme.MissedStyle()
 	if m.Habit.HabitType == store.HabitTypeImprove {
 		missColor = lipgloss.NewStyle()
 	}
-	futureDatesColor := lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
-	weekDaysHeader := "Mon Tue Wed Thu Fri Sat Sun"
+	helpStyle := theme.HelpStyle()
+	locale, _ := getCalendarSettings()
 	monthTitle := ""
 	if m.HasPreviousNbr {
 		monthTitle += "← "
 	}
-	monthTitle += fmt.Sprintf("%s %d", m.FirstDayOfSetMonth.Month(), m.FirstDayOfSetMonth.Year())
+	monthTitle += getMonthTitle(locale, m.FirstDayOfSetMonth.Month(), m.FirstDayOfSetMonth.Year())
 	if m.HasNxtNbr {
 		monthTitle += " →"
 	}
+	calView := renderMonthGrid(m.Habit, monthTitle, m.FirstDayOfSetMonth, m.HeatMap, m.FirstDayOfSetMonth, m.FirstDayOfSetMonth.AddDate(0, 1, -1))
+
+	calView += fmt.Sprintf("Completed: %d\n", m.TotalStreaksInMonth)
+	calView += fmt.Sprintf("Missed: %d\n", m.TotalMissesInMonth)
+	if len(m.StrengthHistory) > 0 {
+		calView += fmt.Sprintf("Strength: %.0f%%\n", m.StrengthHistory[len(m.StrengthHistory)-1])
+		calView += streakColor.Render(renderSparkline(m.StrengthHistory)) + "\n"
+		calView += helpStyle.Render(fmt.Sprintf("last %d days", strengthHistoryDays)) + "\n"
+	}
+	if m.Savings != nil {
+		calView += renderSavings(m.Habit, m.Savings, streakColor, missColor, helpStyle)
+	}
+	for _, goal := range m.Goals {
+		calView += fmt.Sprintf("Goal: %s\n", FormatGoalTarget(goal.Goal))
+		calView += streakColor.Render(RenderProgressBar(goal.Current, goal.Required, goalProgressBarWidth))
+		calView += fmt.Sprintf(" %d/%d\n", goal.Current, goal.Required)
+		calView += helpStyle.Render(FormatGoalPace(goal)) + "\n"
+	}
+
+	helpMsg := "←→ navigate months • q quit"
+	if m.ParentTable != nil {
+		helpMsg = "←→ navigate months • esc back • q quit"
+	}
+
+	calView += "\n" + helpStyle.Render(helpMsg)
+	return calView
+}
+
+// renderMonthGrid draws the titled calendar of the month starting at firstDay, always six weeks tall.
+// heatmap holds the days from from to to, days of the month outside of them are shown like future days.
+func renderMonthGrid(habit generated.Habit, title string, firstDay time.Time, heatmap []bool, from, to time.Time) string {
+	theme := getTheme()
+	weekdayStyle := theme.AccentStyle().Align(lipgloss.Left)
+	streakColor := theme.DoneStyle()
+	missColor := theme.MissedStyle()
+	if habit.HabitType == store.HabitTypeImprove {
+		missColor = lipgloss.NewStyle()
+	}
+	futureDatesColor := theme.MutedStyle()
+	locale, weekStart := getCalendarSettings()
+	weekDaysHeader := getWeekdaysHeader(locale, weekStart)
 	calView := ""
-	calView += lipgloss.
-		NewStyle().
-		Width(len(weekDaysHeader)).
+	calView += theme.AccentStyle().
+		Width(lipgloss.Width(weekDaysHeader)).
 		Align(lipgloss.Center).
-		Foreground(monthTitleColor).
-		Render(monthTitle) + "\n"
-	calView += weekdayStyle.Width(len(weekDaysHeader)).Render(weekDaysHeader)
+		Render(title) + "\n"
+	calView += weekdayStyle.Width(lipgloss.Width(weekDaysHeader)).Render(weekDaysHeader)
 	calView += "\n"
-	dayOfTheWeek := m.FirstDayOfSetMonth.Weekday()
-	if dayOfTheWeek == 0 {
-		dayOfTheWeek = 7
-	}
-	for ; dayOfTheWeek > 1; dayOfTheWeek-- {
-		calView += "    "
-	}
+	calView += strings.Repeat("    ", util.GetWeekdayOffset(firstDay, weekStart))
 	weeksPassed := 0
-	for i, val := range m.HeatMap {
-		date := m.FirstDayOfSetMonth.AddDate(0, 0, i)
-		if date.Day() < 10 {
-			calView += "  "
-		} else {
-			calView += " "
+	daysInMonth := firstDay.AddDate(0, 1, -1).Day()
+	for i := 0; i < daysInMonth; i++ {
+		date := firstDay.AddDate(0, 0, i)
+		// with glyphs the day takes two columns and the glyph the third
+		width := 3
+		if theme.Glyphs {
+			width = 2
+		}
+		calView += strings.Repeat(" ", width-len(strconv.Itoa(date.Day())))
+		val := false
+		if idx := util.GetDayDiff(from, date); idx >= 0 && idx < len(heatmap) {
+			val = heatmap[idx]
 		}
 		style := streakColor
+		glyph := glyphDone
 		if !val {
 			style = missColor
+			glyph = glyphMissed
 		}
 		if util.IsSameDate(date, time.Now()) {
-			style = style.Background(todaysDateBGColor)
+			style = style.Background(themeColor(theme.Accent))
+			if !val {
+				// the day isn't over yet
+				glyph = glyphUntracked
+			}
 		}
 		// Show future dates in gray
 		if util.CompareDate(date, time.Now()) == -1 {
 			style = futureDatesColor
+			glyph = glyphUntracked
 		}
 		// Show dates before habit creation in gray (they don't apply to this habit)
-		if util.CompareDate(date, m.Habit.CreatedAt) == 1 {
+		if util.CompareDate(date, habit.CreatedAt) == 1 {
+			style = futureDatesColor
+			glyph = glyphUntracked
+		}
+		// Show dates outside of the range in gray
+		if util.CompareDate(date, from) == 1 || util.CompareDate(date, to) == -1 {
 			style = futureDatesColor
+			glyph = glyphUntracked
+		}
+		day := fmt.Sprintf("%d", date.Day())
+		if theme.Glyphs {
+			day += glyph
 		}
-		calView += style.Render(fmt.Sprintf("%d", date.Day()))
-		if i != len(m.HeatMap)-1 {
-			if date.Weekday() == time.Sunday {
+		calView += style.Render(day)
+		if i != daysInMonth-1 {
+			if util.GetWeekdayOffset(date, weekStart) == 6 {
 				calView += "\n"
 				weeksPassed++
 			} else {
@@ -219,17 +346,6 @@ func (m StatsModel) View() string {
 	for ; weeksPassed < 6; weeksPassed++ {
 		calView += "\n"
 	}
-
-	calView += fmt.Sprintf("Completed: %d\n", m.TotalStreaksInMonth)
-	calView += fmt.Sprintf("Missed: %d\n", m.TotalMissesInMonth)
-
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
-	helpMsg := "←→ navigate months • q quit"
-	if m.ParentTable != nil {
-		helpMsg = "←→ navigate months • esc back • q quit"
-	}
-
-	calView += "\n" + helpStyle.Render(helpMsg)
 	return calView
 }
 
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// describePhi puts a phi coefficient into words.
+func describePhi(phi float64) string {
+	strength := "no"
+	switch abs := math.Abs(phi); {
+	case abs >= 0.5:
+		strength = "strong"
+	case abs >= 0.3:
+		strength = "moderate"
+	case abs >= 0.1:
+		strength = "weak"
+	}
+	if strength == "no" {
+		return "no correlation"
+	}
+	if phi < 0 {
+		return strength + " negative correlation"
+	}
+	return strength + " positive correlation"
+}
+
+// renderComparison draws the calendars of both habits next to each other, a month per row, followed by how they relate.
+func renderComparison(c *types.HabitComparison) string {
+	theme := getTheme()
+	helpStyle := theme.HelpStyle()
+	accentStyle := theme.AccentStyle()
+	locale, weekStart := getCalendarSettings()
+	columnWidth := lipgloss.Width(getWeekdaysHeader(locale, weekStart))
+	gap := "     "
+	start, end := c.A.RangeStart, c.A.RangeEnd
+
+	view := accentStyle.Render(c.A.Habit.Name+" vs "+c.B.Habit.Name) + " "
+	view += helpStyle.Render(fmt.Sprintf("%s – %s", start.Format("Jan 2, 2006"), end.Format("Jan 2, 2006"))) + "\n\n"
+	names := lipgloss.JoinHorizontal(lipgloss.Top,
+		accentStyle.Bold(true).Width(columnWidth).Align(lipgloss.Center).Render(c.A.Habit.Name),
+		gap,
+		accentStyle.Bold(true).Width(columnWidth).Align(lipgloss.Center).Render(c.B.Habit.Name))
+	rows := []string{names}
+	for month := time.Date(start.Year(), start.Month(), 1, 12, 0, 0, 0, time.Local); util.CompareDate(month, end) >= 0; month = month.AddDate(0, 1, 0) {
+		title := getMonthTitle(locale, month.Month(), month.Year())
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			renderMonthGrid(c.A.Habit, title, month, c.A.Heatmap, start, end),
+			gap,
+			renderMonthGrid(c.B.Habit, title, month, c.B.Heatmap, start, end)))
+	}
+	view += strings.Join(rows, "\n") + "\n"
+
+	if c.Days() == 0 {
+		return view + helpStyle.Render("No days on which both habits were tracked yet.") + "\n"
+	}
+	a, b := c.A.Habit.Name, c.B.Habit.Name
+	view += fmt.Sprintf("Days compared: %d\n", c.Days())
+	view += fmt.Sprintf("Both done: %d (%.0f%%)\n", c.BothDone, c.BothDoneRate())
+	view += fmt.Sprintf("Only %s done: %d\n", a, c.OnlyADone)
+	view += fmt.Sprintf("Only %s done: %d\n", b, c.OnlyBDone)
+	view += fmt.Sprintf("Both missed: %d\n", c.NeitherDone)
+	view += fmt.Sprintf("Same outcome: %.0f%% of days\n", c.Agreement())
+	if c.BothDone+c.OnlyADone > 0 {
+		view += fmt.Sprintf("When %s done, %s done %.0f%%\n", a, b, c.BDoneWhenADone())
+	}
+	if c.OnlyBDone+c.NeitherDone > 0 {
+		view += fmt.Sprintf("When %s missed, %s done %.0f%%\n", a, b, c.BDoneWhenAMissed())
+	}
+	if c.BothDone+c.OnlyBDone > 0 {
+		view += fmt.Sprintf("When %s done, %s done %.0f%%\n", b, a, c.ADoneWhenBDone())
+	}
+	if c.OnlyADone+c.NeitherDone > 0 {
+		view += fmt.Sprintf("When %s missed, %s done %.0f%%\n", b, a, c.ADoneWhenBMissed())
+	}
+	if phi, ok := c.Phi(); ok {
+		view += fmt.Sprintf("Phi coefficient: %.2f ", phi) + helpStyle.Render(describePhi(phi)) + "\n"
+	} else {
+		view += "Phi coefficient: " + helpStyle.Render("undefined, a habit was always done or always missed") + "\n"
+	}
+	return view
+}
+
+// RenderCompareView prints the comparison of two habits, like the range view it isn't interactive.
+func RenderCompareView(w io.Writer, comparison *types.HabitComparison) error {
+	_, err := fmt.Fprint(w, renderComparison(comparison))
+	return err
+}
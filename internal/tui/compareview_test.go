@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribePhi(t *testing.T) {
+	assert.Equal(t, "no correlation", describePhi(0.05))
+	assert.Equal(t, "weak positive correlation", describePhi(0.2))
+	assert.Equal(t, "moderate negative correlation", describePhi(-0.4))
+	assert.Equal(t, "strong positive correlation", describePhi(0.9))
+}
+
+func TestRenderComparison(t *testing.T) {
+	themeOnce.Do(func() { currentTheme = themes["default"] })
+	calendarOnce.Do(func() { calendarLocale, _ = util.GetLocale(util.DefaultLocale); calendarWeekStart = time.Monday })
+
+	start := time.Date(2025, 11, 1, 12, 0, 0, 0, time.Local)
+	end := time.Date(2025, 12, 10, 12, 0, 0, 0, time.Local)
+	stats := func(name string) *types.HabitStatsForRange {
+		return &types.HabitStatsForRange{
+			Habit:      generated.Habit{Name: name, CreatedAt: start},
+			Heatmap:    make([]bool, 40),
+			RangeStart: start,
+			RangeEnd:   end,
+		}
+	}
+	view := renderComparison(&types.HabitComparison{A: stats("gym"), B: stats("sleep"), BothDone: 3, OnlyADone: 2, OnlyBDone: 2, NeitherDone: 3})
+	assert.Contains(t, view, "gym vs sleep")
+	assert.Contains(t, view, "November 2025")
+	assert.Contains(t, view, "December 2025")
+	assert.Contains(t, view, "When gym done, sleep done 60%\n")
+	assert.Contains(t, view, "Phi coefficient: 0.20")
+
+	view = renderComparison(&types.HabitComparison{A: stats("gym"), B: stats("sleep")})
+	assert.Contains(t, view, "No days on which both habits were tracked yet.")
+}
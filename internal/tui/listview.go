@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strings"
 
 	"github.com/Atharva21/streakr/internal/service"
 	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/paginator"
 	tea "github.com/charmbracelet/bubbletea"
@@ -26,6 +28,7 @@ var docStyle = lipgloss.NewStyle().Margin(1, 2)
 
 type ListModel struct {
 	Ctx         context.Context
+	Tag         string // only list habits with this tag if set
 	List        list.Model
 	Initialized bool
 }
@@ -36,7 +39,13 @@ type ListLoadedMsg struct {
 
 func (m ListModel) Init() tea.Cmd {
 	return func() tea.Msg {
-		habits, err := service.ListHabits(m.Ctx)
+		var habits []generated.Habit
+		var err error
+		if m.Tag != "" {
+			habits, err = service.ListHabitsByTag(m.Ctx, m.Tag)
+		} else {
+			habits, err = service.ListHabits(m.Ctx)
+		}
 		if err != nil {
 			return viewErrorMsg{err: err}
 		}
@@ -50,32 +59,40 @@ func (m ListModel) Init() tea.Cmd {
 			if habit.Description.Valid {
 				description = habit.Description.String
 			}
+			tags, err := service.GetTagsForHabit(m.Ctx, habit)
+			if err != nil {
+				return viewErrorMsg{err: err}
+			}
+			if len(tags) > 0 {
+				description = strings.TrimSpace(description + " #" + strings.Join(tags, " #"))
+			}
 			items = append(items, habitItem{
 				title: habit.Name,
 				desc:  description,
 			})
 		}
 
+		theme := getTheme()
 		delegate := list.NewDefaultDelegate()
 
-		delegate.Styles.SelectedTitle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#5d8addff")). // Your desired color
+		delegate.Styles.SelectedTitle = theme.AccentStyle().
 			Bold(true).
 			BorderLeft(true).
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#5d8addff")).
+			BorderForeground(themeColor(theme.Accent)).
 			PaddingLeft(1)
 
-		delegate.Styles.SelectedDesc = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#cccccc")).
+		delegate.Styles.SelectedDesc = theme.SubtleStyle().
 			PaddingLeft(2)
 
 		listModel := list.New(items, delegate, 80, 15)
 		listModel.Title = "My Habits"
+		if m.Tag != "" {
+			listModel.Title = "My Habits #" + m.Tag
+		}
 
 		// Title style
-		titleStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#5d8addff")).
+		titleStyle := theme.AccentStyle().
 			Padding(0, 1)
 		listModel.Styles.Title = titleStyle
 
@@ -132,11 +149,11 @@ func (m ListModel) View() string {
 	return ""
 }
 
-func RenderListView(appContext context.Context) error {
+func RenderListView(appContext context.Context, tag string) error {
 	if appContext == nil {
 		return errors.New("appContext cannot be nil to render listview")
 	}
-	p := tea.NewProgram(ListModel{Ctx: appContext}, tea.WithAltScreen())
+	p := tea.NewProgram(ListModel{Ctx: appContext, Tag: tag}, tea.WithAltScreen())
 	go func() {
 		<-appContext.Done()
 		p.Send(tea.Quit())
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	calendarOnce      sync.Once
+	calendarLocale    util.Locale
+	calendarWeekStart time.Weekday
+)
+
+// getCalendarSettings resolves the locale and week_start settings once, config.json fails to load with invalid ones.
+func getCalendarSettings() (util.Locale, time.Weekday) {
+	calendarOnce.Do(func() {
+		settings := config.GetStreakrConfig().Settings
+		calendarLocale, calendarWeekStart = settings.GetLocale(), settings.GetWeekStart()
+	})
+	return calendarLocale, calendarWeekStart
+}
+
+// getWeekdaysHeader lists the short weekday names from weekStart, each padded to a day cell of the calendar.
+func getWeekdaysHeader(locale util.Locale, weekStart time.Weekday) string {
+	names := make([]string, 0, 7)
+	for i := range 7 {
+		name := locale.ShortWeekdays[(int(weekStart)+i)%7]
+		names = append(names, name+strings.Repeat(" ", max(0, 3-lipgloss.Width(name))))
+	}
+	return strings.Join(names, " ")
+}
+
+func getMonthTitle(locale util.Locale, month time.Month, year int) string {
+	return fmt.Sprintf("%s %d", locale.MonthName(month), year)
+}
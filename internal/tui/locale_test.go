@@ -0,0 +1,17 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWeekdaysHeader(t *testing.T) {
+	english, _ := util.GetLocale("en")
+	assert.Equal(t, "Mon Tue Wed Thu Fri Sat Sun", getWeekdaysHeader(english, time.Monday))
+	assert.Equal(t, "Sun Mon Tue Wed Thu Fri Sat", getWeekdaysHeader(english, time.Sunday))
+	german, _ := util.GetLocale("de")
+	assert.Equal(t, "Sa  So  Mo  Di  Mi  Do  Fr ", getWeekdaysHeader(german, time.Saturday))
+}
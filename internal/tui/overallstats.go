@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/types"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,14 +20,27 @@ type statsLoadedMsg struct {
 	table table.Model
 }
 
+// subtotalRowPrefix marks per tag subtotal rows, they can't be opened like habit rows.
+const subtotalRowPrefix = "Σ #"
+
+// minHabitColumnWidth fits a 20 character name, the column grows for wider names.
+const minHabitColumnWidth = 22
+
 type OverallStats struct {
 	Ctx   context.Context
+	Tag   string // only show habits with this tag if set
 	table table.Model
 }
 
 func (m OverallStats) Init() tea.Cmd {
 	return func() tea.Msg {
-		s, err := service.GetOverallStats(m.Ctx)
+		var s *types.OverallStats
+		var err error
+		if m.Tag != "" {
+			s, err = service.GetOverallStatsByTag(m.Ctx, m.Tag)
+		} else {
+			s, err = service.GetOverallStats(m.Ctx)
+		}
 		if err != nil {
 			slog.Error("error in getting overall stats from service", "err", err.Error())
 			return viewErrorMsg{err: err}
@@ -36,12 +51,18 @@ func (m OverallStats) Init() tea.Cmd {
 		sort.Slice(s.HabitInfos, func(i, j int) bool {
 			return s.HabitInfos[i].CurrentStreak > s.HabitInfos[j].CurrentStreak
 		})
+		habitColumnWidth := minHabitColumnWidth
+		for _, habitInfo := range s.HabitInfos {
+			// names can hold wide characters like emoji, measure cells instead of bytes
+			habitColumnWidth = max(habitColumnWidth, lipgloss.Width(habitInfo.Habit.Name)+2)
+		}
 		cols := []table.Column{
-			{Title: "Habit", Width: 22},
+			{Title: "Habit", Width: habitColumnWidth},
 			{Title: "Current", Width: 9},
 			{Title: "Max", Width: 9},
 			{Title: "Total", Width: 6},
 			{Title: "Missed", Width: 6},
+			{Title: "Strength", Width: 8},
 		}
 		rows := []table.Row{}
 		for _, habitInfo := range s.HabitInfos {
@@ -57,6 +78,17 @@ func (m OverallStats) Init() tea.Cmd {
 				maxStreakStr,
 				fmt.Sprintf("%d", habitInfo.TotalPerformedDays),
 				fmt.Sprintf("%d", habitInfo.TotalMissedDays),
+				fmt.Sprintf("%.0f%%", habitInfo.Strength),
+			})
+		}
+		for _, subtotal := range s.TagSubtotals {
+			rows = append(rows, table.Row{
+				fmt.Sprintf("%s%s (%d)", subtotalRowPrefix, subtotal.Tag, subtotal.HabitCount),
+				"",
+				"",
+				fmt.Sprintf("%d", subtotal.TotalPerformedDays),
+				fmt.Sprintf("%d", subtotal.TotalMissedDays),
+				fmt.Sprintf("%.0f%%", subtotal.Strength),
 			})
 		}
 		t := table.New(
@@ -70,10 +102,15 @@ func (m OverallStats) Init() tea.Cmd {
 			BorderStyle(lipgloss.NormalBorder()).
 			BorderBottom(true).
 			Bold(false)
+		theme := getTheme()
 		style.Selected = style.Selected.
-			Background(lipgloss.Color("#5d8addff")).
-			Foreground(lipgloss.Color("15")).
+			Background(themeColor(theme.Accent)).
+			Foreground(themeColor(theme.SelectedText)).
 			Bold(false)
+		if theme.Accent == "" {
+			// without colors the selection would be invisible
+			style.Selected = style.Selected.Reverse(true)
+		}
 		t.SetStyles(style)
 		return statsLoadedMsg{
 			table: t,
@@ -93,6 +130,9 @@ func (m OverallStats) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "enter":
 			habitName := m.table.SelectedRow()[0]
+			if strings.HasPrefix(habitName, subtotalRowPrefix) {
+				return m, nil
+			}
 			habit, err := service.GetHabitByName(m.Ctx, habitName)
 			if err != nil {
 				slog.Error("error in getting habit by name in statsview", "err", err.Error())
@@ -115,17 +155,18 @@ func (m OverallStats) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m OverallStats) View() string {
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+	helpStyle := getTheme().HelpStyle()
 	helpMsg := helpStyle.Render("↑↓ navigate • enter select • q/esc quit")
 	return lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).Render(m.table.View()) + "\n" + helpMsg
 }
 
-func RenderOverallStats(appContext context.Context) error {
+func RenderOverallStats(appContext context.Context, tag string) error {
 	if appContext == nil {
 		return errors.New("Context cannot be nil")
 	}
 	overallStats := OverallStats{
 		Ctx: appContext,
+		Tag: tag,
 	}
 	p := tea.NewProgram(overallStats, tea.WithAltScreen())
 	go func() {
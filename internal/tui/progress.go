@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+)
+
+// RenderProgressBar draws current/target as a bar of width cells, clamped to full.
+func RenderProgressBar(current, target int64, width int) string {
+	filled := width
+	if target > 0 && current < target {
+		filled = int(max(current, 0) * int64(width) / target)
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
+
+// FormatGoalTarget describes what a goal asks for, e.g. "100 days by 2026-12-31".
+func FormatGoalTarget(goal generated.Goal) string {
+	deadline := goal.Deadline.Format(time.DateOnly)
+	switch goal.Kind {
+	case store.GoalKindStreak:
+		return fmt.Sprintf("%d day streak by %s", goal.Target, deadline)
+	case store.GoalKindRate:
+		return fmt.Sprintf("%d%% of days by %s", goal.Target, deadline)
+	default:
+		return fmt.Sprintf("%d days by %s", goal.Target, deadline)
+	}
+}
+
+// FormatGoalPace summarizes the pace needed to reach a goal, e.g. "need 4.2 days/week · projected 2026-11-03".
+func FormatGoalPace(progress types.GoalProgress) string {
+	switch {
+	case progress.Achieved:
+		return "achieved 🎉"
+	case progress.Expired:
+		return "missed the deadline"
+	case progress.RequiredPerWeek > 7:
+		return "out of reach before the deadline"
+	}
+	pace := fmt.Sprintf("need %.1f days/week", progress.RequiredPerWeek)
+	if progress.Goal.Kind == store.GoalKindStreak {
+		pace = "need every day"
+	}
+	if progress.ProjectedDate.IsZero() {
+		return pace + " · no projection yet"
+	}
+	pace += " · projected " + progress.ProjectedDate.Format(time.DateOnly)
+	if !progress.OnTrack {
+		pace += " (behind)"
+	}
+	return pace
+}
+
+// FormatMinutes renders a number of minutes as 45m or 3h20m.
+func FormatMinutes(minutes int64) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
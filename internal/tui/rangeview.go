@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/service"
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// rangeMonthsPerRow is the number of month calendars printed next to each other.
+const rangeMonthsPerRow = 3
+
+// renderRangeStats draws a calendar for every month the range touches, followed by the figures of the whole range.
+func renderRangeStats(stats *types.HabitStatsForRange) string {
+	theme := getTheme()
+	helpStyle := theme.HelpStyle()
+	locale, _ := getCalendarSettings()
+
+	months := make([]string, 0)
+	for month := time.Date(stats.RangeStart.Year(), stats.RangeStart.Month(), 1, 12, 0, 0, 0, time.Local); util.CompareDate(month, stats.RangeEnd) >= 0; month = month.AddDate(0, 1, 0) {
+		title := getMonthTitle(locale, month.Month(), month.Year())
+		months = append(months, renderMonthGrid(stats.Habit, title, month, stats.Heatmap, stats.RangeStart, stats.RangeEnd))
+	}
+	rows := make([]string, 0, (len(months)+rangeMonthsPerRow-1)/rangeMonthsPerRow)
+	for i := 0; i < len(months); i += rangeMonthsPerRow {
+		row := make([]string, 0, 2*rangeMonthsPerRow-1)
+		for j, month := range months[i:min(i+rangeMonthsPerRow, len(months))] {
+			if j > 0 {
+				row = append(row, "   ")
+			}
+			row = append(row, month)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+
+	view := theme.AccentStyle().Render(stats.Habit.Name) + " "
+	view += helpStyle.Render(fmt.Sprintf("%s – %s", stats.RangeStart.Format("Jan 2, 2006"), stats.RangeEnd.Format("Jan 2, 2006"))) + "\n\n"
+	view += strings.Join(rows, "\n") + "\n"
+	view += fmt.Sprintf("Completed: %d\n", stats.TotalStreakDaysInRange)
+	view += fmt.Sprintf("Missed: %d\n", stats.TotalMissesInRange)
+	view += fmt.Sprintf("Completion: %.0f%%\n", stats.Completion())
+	view += fmt.Sprintf("Longest streak: %d\n", stats.LongestStreak())
+	return view
+}
+
+// RenderRangeStatsView prints the calendars and aggregated figures of habit from start to end.
+// Unlike the single month view it isn't interactive, so long ranges can scroll and be piped.
+func RenderRangeStatsView(appContext context.Context, w io.Writer, habit generated.Habit, start, end time.Time) error {
+	stats, err := service.GetHabitStatsForRange(appContext, habit.Slug, start, end)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, renderRangeStats(stats))
+	return err
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Atharva21/streakr/internal/store/generated"
+	"github.com/Atharva21/streakr/internal/types"
+	"github.com/Atharva21/streakr/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderRangeStats(t *testing.T) {
+	// skip the config, the default theme and english calendar are what the test expects
+	themeOnce.Do(func() { currentTheme = themes["default"] })
+	calendarOnce.Do(func() { calendarLocale, _ = util.GetLocale(util.DefaultLocale); calendarWeekStart = time.Monday })
+	start := time.Date(2025, 1, 30, 12, 0, 0, 0, time.Local)
+	end := time.Date(2025, 3, 2, 12, 0, 0, 0, time.Local)
+	heatmap := make([]bool, 32)
+	for i := 3; i < 8; i++ {
+		heatmap[i] = true
+	}
+	view := renderRangeStats(&types.HabitStatsForRange{
+		Habit:                  generated.Habit{Name: "reading", CreatedAt: start.AddDate(-1, 0, 0)},
+		Heatmap:                heatmap,
+		TotalStreakDaysInRange: 5,
+		TotalMissesInRange:     27,
+		RangeStart:             start,
+		RangeEnd:               end,
+	})
+	assert.Contains(t, view, "Jan 30, 2025 – Mar 2, 2025")
+	for _, month := range []string{"January 2025", "February 2025", "March 2025"} {
+		assert.Contains(t, view, month)
+	}
+	assert.NotContains(t, view, "April 2025")
+	assert.Contains(t, view, "Completed: 5\n")
+	assert.Contains(t, view, "Completion: 16%\n")
+	assert.Contains(t, view, "Longest streak: 5\n")
+}
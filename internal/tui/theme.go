@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/Atharva21/streakr/internal/heatmap"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors of every view, an empty color leaves the terminal default.
+type Theme struct {
+	Accent       string // titles, weekday header, today and selections
+	SelectedText string // text on the accent background
+	Done         string // performed and clean days
+	Missed       string // missed days and slip-ups
+	Muted        string // days before the habit was created and in the future
+	Help         string
+	Subtle       string // descriptions of selected list items
+	// Glyphs marks days with ✓ and ✗ so their meaning doesn't depend on color.
+	Glyphs bool
+}
+
+const (
+	ThemeAuto           = "auto"
+	ThemeDefault        = "default"
+	ThemeLight          = "light"
+	ThemeHighContrast   = "high-contrast"
+	ThemeColorblindSafe = "colorblind-safe"
+)
+
+var themes = map[string]Theme{
+	ThemeDefault: {
+		Accent:       "#5d8addff",
+		SelectedText: "15",
+		Done:         "#25a425ff",
+		Missed:       "#c25252ff",
+		Muted:        "#444444",
+		Help:         "#666666",
+		Subtle:       "#cccccc",
+	},
+	ThemeLight: {
+		Accent:       "#2f5fb3",
+		SelectedText: "15",
+		Done:         "#1a7f1a",
+		Missed:       "#b02a2a",
+		Muted:        "#bbbbbb",
+		Help:         "#888888",
+		Subtle:       "#444444",
+	},
+	ThemeHighContrast: {
+		Accent:       "#00ffff",
+		SelectedText: "0",
+		Done:         "#00ff00",
+		Missed:       "#ff0000",
+		Muted:        "#808080",
+		Help:         "#c0c0c0",
+		Subtle:       "#ffffff",
+	},
+	// blue and orange from the Okabe-Ito palette instead of green and red
+	ThemeColorblindSafe: {
+		Accent:       "#56b4e9",
+		SelectedText: "0",
+		Done:         "#0072b2",
+		Missed:       "#e69f00",
+		Muted:        "#444444",
+		Help:         "#666666",
+		Subtle:       "#cccccc",
+	},
+}
+
+// ThemeNames lists the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveTheme picks the theme for settings. Without a name the terminal background decides between
+// default and light, with noColor every color is dropped and glyphs are turned on.
+func resolveTheme(settings config.ThemeSettings, noColor bool, hasDarkBackground func() bool) (Theme, error) {
+	name := strings.ToLower(strings.TrimSpace(settings.Name))
+	if name == "" || name == ThemeAuto {
+		name = ThemeDefault
+		if !noColor && !hasDarkBackground() {
+			name = ThemeLight
+		}
+	}
+	theme, ok := themes[name]
+	if !ok {
+		return themes[ThemeDefault], fmt.Errorf("unknown theme %q, available themes: %s", settings.Name, strings.Join(ThemeNames(), ", "))
+	}
+	for _, override := range []struct {
+		color *string
+		value string
+	}{
+		{&theme.Accent, settings.Colors.Accent},
+		{&theme.Done, settings.Colors.Done},
+		{&theme.Missed, settings.Colors.Missed},
+		{&theme.Muted, settings.Colors.Muted},
+		{&theme.Help, settings.Colors.Help},
+		{&theme.Subtle, settings.Colors.Subtle},
+	} {
+		if override.value != "" {
+			*override.color = override.value
+		}
+	}
+	theme.Glyphs = settings.Glyphs
+	if noColor {
+		theme = Theme{Glyphs: true}
+	}
+	return theme, nil
+}
+
+var (
+	themeOnce    sync.Once
+	currentTheme Theme
+)
+
+// getTheme resolves the configured theme on first use, so commands without a view never query the terminal.
+func getTheme() Theme {
+	themeOnce.Do(func() {
+		var err error
+		currentTheme, err = resolveTheme(config.GetStreakrConfig().Settings.Theme, os.Getenv("NO_COLOR") != "", lipgloss.HasDarkBackground)
+		if err != nil {
+			slog.Warn("falling back to the default theme", "err", err.Error())
+		}
+	})
+	return currentTheme
+}
+
+// HeatmapPalette is the configured theme as colors for heatmap images, which are looked at outside the
+// terminal: without a theme name the default theme is used, or the light one for images on a light background,
+// and NO_COLOR doesn't apply.
+func HeatmapPalette(lightBackground bool) heatmap.Palette {
+	palette, err := heatmapPalette(config.GetStreakrConfig().Settings.Theme, lightBackground)
+	if err != nil {
+		slog.Warn("falling back to the default theme", "err", err.Error())
+	}
+	return palette
+}
+
+// heatmapPalette builds the palette of settings. Colors that images can't use, like ANSI color numbers
+// in the theme's color overrides, keep the color of the built-in theme.
+func heatmapPalette(settings config.ThemeSettings, lightBackground bool) (heatmap.Palette, error) {
+	darkBackground := func() bool { return !lightBackground }
+	theme, err := resolveTheme(settings, false, darkBackground)
+	builtin, _ := resolveTheme(config.ThemeSettings{Name: settings.Name}, false, darkBackground)
+	hexColor := func(value, fallback string) string {
+		if _, err := heatmap.ParseColor(value); err != nil {
+			return fallback
+		}
+		return value
+	}
+	return heatmap.Palette{
+		Done:      hexColor(theme.Done, builtin.Done),
+		Missed:    hexColor(theme.Missed, builtin.Missed),
+		Untracked: hexColor(theme.Muted, builtin.Muted),
+		Text:      hexColor(theme.Help, builtin.Help),
+	}, err
+}
+
+// themeColor turns a theme color into a lipgloss color, empty colors use the terminal default.
+func themeColor(value string) lipgloss.TerminalColor {
+	if value == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(value)
+}
+
+func (t Theme) foreground(value string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(themeColor(value))
+}
+
+func (t Theme) AccentStyle() lipgloss.Style { return t.foreground(t.Accent) }
+func (t Theme) DoneStyle() lipgloss.Style   { return t.foreground(t.Done) }
+func (t Theme) MissedStyle() lipgloss.Style { return t.foreground(t.Missed) }
+func (t Theme) MutedStyle() lipgloss.Style  { return t.foreground(t.Muted) }
+func (t Theme) HelpStyle() lipgloss.Style   { return t.foreground(t.Help) }
+func (t Theme) SubtleStyle() lipgloss.Style { return t.foreground(t.Subtle) }
+
+const (
+	glyphDone      = "✓"
+	glyphMissed    = "✗"
+	glyphUntracked = " "
+)
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/Atharva21/streakr/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTheme(t *testing.T) {
+	dark := func() bool { return true }
+	light := func() bool { return false }
+
+	theme, err := resolveTheme(config.ThemeSettings{}, false, dark)
+	assert.NoError(t, err)
+	assert.Equal(t, themes[ThemeDefault], theme)
+
+	theme, err = resolveTheme(config.ThemeSettings{Name: "auto"}, false, light)
+	assert.NoError(t, err)
+	assert.Equal(t, themes[ThemeLight], theme)
+
+	theme, err = resolveTheme(config.ThemeSettings{Name: "Colorblind-Safe", Glyphs: true, Colors: config.ThemeColors{Done: "#ffffff"}}, false, dark)
+	assert.NoError(t, err)
+	assert.Equal(t, "#ffffff", theme.Done)
+	assert.Equal(t, themes[ThemeColorblindSafe].Missed, theme.Missed)
+	assert.True(t, theme.Glyphs)
+
+	// NO_COLOR drops every color and relies on glyphs, without asking the terminal
+	theme, err = resolveTheme(config.ThemeSettings{}, true, func() bool {
+		t.Fatal("the background should not be queried")
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Theme{Glyphs: true}, theme)
+
+	theme, err = resolveTheme(config.ThemeSettings{Name: "solarized"}, false, dark)
+	assert.Error(t, err)
+	assert.Equal(t, themes[ThemeDefault], theme)
+}
+
+func TestHeatmapPalette(t *testing.T) {
+	palette, err := heatmapPalette(config.ThemeSettings{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, themes[ThemeLight].Done, palette.Done)
+	assert.NoError(t, palette.Validate())
+
+	// an ANSI color override can't be drawn, so the theme's own color stays
+	palette, err = heatmapPalette(config.ThemeSettings{Name: ThemeColorblindSafe, Colors: config.ThemeColors{Done: "4", Missed: "#ff8800"}}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, themes[ThemeColorblindSafe].Done, palette.Done)
+	assert.Equal(t, "#ff8800", palette.Missed)
+	assert.Equal(t, themes[ThemeColorblindSafe].Muted, palette.Untracked)
+	assert.Equal(t, themes[ThemeColorblindSafe].Help, palette.Text)
+
+	palette, err = heatmapPalette(config.ThemeSettings{Name: "solarized"}, false)
+	assert.Error(t, err)
+	assert.Equal(t, themes[ThemeDefault].Done, palette.Done)
+}
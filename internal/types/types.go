@@ -1,6 +1,7 @@
 package types
 
 import (
+	"math"
 	"time"
 
 	"github.com/Atharva21/streakr/internal/store/generated"
@@ -12,6 +13,8 @@ type HabitInfo struct {
 	MaxStreak          int64
 	TotalPerformedDays int64
 	TotalMissedDays    int64
+	Strength           float64 // 0-100, exponentially smoothed daily completion
+	Tags               []string
 }
 
 type HabitStatsForRange struct {
@@ -23,6 +26,233 @@ type HabitStatsForRange struct {
 	RangeEnd               time.Time
 }
 
+// Completion is the percentage of tracked days in the range that were done.
+func (s HabitStatsForRange) Completion() float64 {
+	return percentage(s.TotalStreakDaysInRange, s.TotalStreakDaysInRange+s.TotalMissesInRange)
+}
+
+// LongestStreak is the longest run of done days inside the range.
+func (s HabitStatsForRange) LongestStreak() int {
+	longest, run := 0, 0
+	for _, done := range s.Heatmap {
+		if !done {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return longest
+}
+
 type OverallStats struct {
-	HabitInfos []HabitInfo
+	HabitInfos   []HabitInfo
+	TagSubtotals []TagSubtotal
+}
+
+// TagSubtotal aggregates the habits sharing a tag.
+type TagSubtotal struct {
+	Tag                string
+	HabitCount         int
+	TotalPerformedDays int64
+	TotalMissedDays    int64
+	Strength           float64 // average over the tag's habits
+}
+
+// StatusSummary is the one line summary used by `streakr status`, fields are exposed to the format template.
+type StatusSummary struct {
+	Done       int64 // improve habits logged today
+	Total      int64 // total improve habits
+	Remaining  int64 // improve habits yet to be logged today
+	BestStreak int64 // highest running streak across all habits
+}
+
+type DueReminder struct {
+	Habit    generated.Habit
+	RemindAt string
+}
+
+// AchievementProgress describes one achievement, either for a single habit or across all habits (HabitName empty).
+type AchievementProgress struct {
+	Code        string
+	Title       string
+	Description string
+	HabitName   string
+	Unlocked    bool
+	UnlockedAt  time.Time
+	Current     int64
+	Target      int64
+}
+
+type Routine struct {
+	Name       string
+	HabitNames []string
+}
+
+// RoutineProgress counts the improve habits of a routine logged today.
+type RoutineProgress struct {
+	Name  string
+	Done  int64
+	Total int64
+}
+
+// GoalProgress describes how far a habit is towards a goal and the pace needed to reach it by the deadline.
+type GoalProgress struct {
+	Goal            generated.Goal
+	HabitName       string
+	Current         int64   // days done, best streak or completed days depending on the goal kind
+	Required        int64   // value of Current that completes the goal
+	Percent         float64 // 0-100
+	Rate            float64 // completion rate (0-100) so far, for rate goals
+	RequiredPerWeek float64 // days per week needed from now on to make the deadline
+	ProjectedDate   time.Time
+	Achieved        bool
+	OnTrack         bool // the projected date is on or before the deadline
+	Expired         bool // deadline passed without achieving the goal
+}
+
+// QuitHabitSavings is what staying clean of a quit habit saved, and how slip-ups trend week over week.
+type QuitHabitSavings struct {
+	CleanDays      int64
+	MoneySaved     float64 // zero unless the habit has a cost per occurrence
+	MinutesSaved   int64   // zero unless the habit has a time per occurrence
+	TotalSlipups   int64
+	SlipupsPerWeek []int64 // oldest first, the last entry is the week ending today
+}
+
+// SyncOp is one change in a device's sync log, each op is written as a json line.
+type SyncOp struct {
+	Device               string    `json:"device"`
+	Seq                  int64     `json:"seq"`
+	Time                 time.Time `json:"time"`
+	Op                   string    `json:"op"`
+	Habit                string    `json:"habit"`         // slug of the habit
+	Day                  string    `json:"day,omitempty"` // YYYY-MM-DD for day ops
+	Count                int64     `json:"count,omitempty"`
+	Name                 string    `json:"name,omitempty"`
+	Description          string    `json:"description,omitempty"`
+	HabitType            string    `json:"habit_type,omitempty"`
+	CreatedAt            time.Time `json:"created_at,omitzero"`
+	CostPerOccurrence    float64   `json:"cost_per_occurrence,omitempty"`
+	MinutesPerOccurrence int64     `json:"minutes_per_occurrence,omitempty"`
+}
+
+type SyncResult struct {
+	// Applied are the incoming ops that won their conflicts, Overridden lost to a later change.
+	Applied    []SyncOp
+	Overridden int
+	// Outgoing is the number of local ops written to LogPath.
+	Outgoing int
+	LogPath  string
+}
+
+// CalendarEvent is an all-day event for calendar export, a streak of an improve habit or a slip-up of a quit habit.
+type CalendarEvent struct {
+	UID     string // stable across exports so calendar apps update events instead of duplicating them
+	Summary string
+	Start   time.Time
+	Days    int
+}
+
+// Report summarizes every habit over a week, month or year next to the period before it.
+// Fields are exposed to the report templates.
+type Report struct {
+	Period        string // week, month or year
+	Start         time.Time
+	End           time.Time
+	PreviousStart time.Time
+	PreviousEnd   time.Time
+	Today         time.Time
+	WeekStart     time.Weekday
+	Habits        []HabitReport
+	Best          *HabitReport // highest completion, nil without tracked habits
+	Worst         *HabitReport // lowest completion, nil with fewer than two tracked habits
+}
+
+type HabitReport struct {
+	HabitInfo
+	Done                  int     // days done (clean days for quit habits) in the period
+	Tracked               int     // days the habit was tracked in the period, up to today
+	Completion            float64 // 0-100
+	PreviousCompletion    float64
+	HasPrevious           bool // the habit was tracked in the previous period
+	LongestStreak         int  // longest run of done days in the period
+	PreviousLongestStreak int
+	Heatmap               []bool // a day per entry from Start to End
+}
+
+// StreakChange is the longest streak of the period against the previous one.
+func (r HabitReport) StreakChange() int {
+	return r.LongestStreak - r.PreviousLongestStreak
+}
+
+// CompletionChange is the completion of the period against the previous one, in percentage points.
+func (r HabitReport) CompletionChange() float64 {
+	return r.Completion - r.PreviousCompletion
+}
+
+// HabitComparison counts the days two habits were both tracked by whether each was done (clean for quit habits).
+type HabitComparison struct {
+	A           *HabitStatsForRange
+	B           *HabitStatsForRange
+	Compared    []bool // a day per entry from RangeStart to RangeEnd, true when both habits were tracked on it
+	BothDone    int
+	OnlyADone   int
+	OnlyBDone   int
+	NeitherDone int
+}
+
+// Days is the number of days both habits were tracked.
+func (c HabitComparison) Days() int {
+	return c.BothDone + c.OnlyADone + c.OnlyBDone + c.NeitherDone
+}
+
+// Agreement is the percentage of compared days on which both habits were done or both were missed.
+func (c HabitComparison) Agreement() float64 {
+	return percentage(c.BothDone+c.NeitherDone, c.Days())
+}
+
+// BothDoneRate is the percentage of compared days on which both habits were done.
+func (c HabitComparison) BothDoneRate() float64 {
+	return percentage(c.BothDone, c.Days())
+}
+
+// BDoneWhenADone is the percentage of days A was done on which B was done too.
+func (c HabitComparison) BDoneWhenADone() float64 {
+	return percentage(c.BothDone, c.BothDone+c.OnlyADone)
+}
+
+// BDoneWhenAMissed is the percentage of days A was missed on which B was done.
+func (c HabitComparison) BDoneWhenAMissed() float64 {
+	return percentage(c.OnlyBDone, c.OnlyBDone+c.NeitherDone)
+}
+
+// ADoneWhenBDone is the percentage of days B was done on which A was done too.
+func (c HabitComparison) ADoneWhenBDone() float64 {
+	return percentage(c.BothDone, c.BothDone+c.OnlyBDone)
+}
+
+// ADoneWhenBMissed is the percentage of days B was missed on which A was done.
+func (c HabitComparison) ADoneWhenBMissed() float64 {
+	return percentage(c.OnlyADone, c.OnlyADone+c.NeitherDone)
+}
+
+// Phi is the phi coefficient of the two habits, from -1 (one is done exactly when the other is missed)
+// to 1 (done on the same days). It's not defined, and ok is false, when a habit was always done or always missed.
+func (c HabitComparison) Phi() (phi float64, ok bool) {
+	aDone, aMissed := c.BothDone+c.OnlyADone, c.OnlyBDone+c.NeitherDone
+	bDone, bMissed := c.BothDone+c.OnlyBDone, c.OnlyADone+c.NeitherDone
+	denominator := float64(aDone) * float64(aMissed) * float64(bDone) * float64(bMissed)
+	if denominator == 0 {
+		return 0, false
+	}
+	numerator := float64(c.BothDone)*float64(c.NeitherDone) - float64(c.OnlyADone)*float64(c.OnlyBDone)
+	return numerator / math.Sqrt(denominator), true
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
 }
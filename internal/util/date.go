@@ -24,6 +24,12 @@ func GetPrevDayOf(t time.Time) time.Time {
 	return t.AddDate(0, 0, -1)
 }
 
+// GetNoonOf returns noon local time of the day, dates stored at noon can't shift a day
+// in DATE() conversions or when compared exactly.
+func GetNoonOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.Local)
+}
+
 func GetDateWithDaysDiff(t time.Time, days int) time.Time {
 	return t.AddDate(0, 0, days)
 }
@@ -0,0 +1,139 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	lastRangePattern    = regexp.MustCompile(`^(?:last-)?([0-9]+)([dwmy])$`)
+	quarterPattern      = regexp.MustCompile(`^(?:([0-9]{4})-)?q([1-4])$`)
+	yearPattern         = regexp.MustCompile(`^[0-9]{4}$`)
+	monthPattern        = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}$`)
+	dateRangeFormatHint = "YYYY-MM-DD, YYYY-MM, YYYY, q1-q4, YYYY-q3, today, yesterday, this-week, last-week, this-month, last-month, this-year, last-year or last-30d (or 30d)"
+)
+
+// ParseDateRange parses an ISO date (2026-03-01), month (2026-03) or year (2026), a quarter of this or
+// another year (q3, 2026-q3), today, yesterday, this-/last-week, -month or -year, or the last N days,
+// weeks, months or years up to today (last-30d, last-4w, last-6m, last-1y, or 30d, 4w, 6m, 1y). The days are at noon.
+func ParseDateRange(value string, today time.Time, weekStart time.Weekday) (time.Time, time.Time, error) {
+	today = GetNoonOf(today)
+	value = strings.ToLower(strings.TrimSpace(value))
+	invalid := fmt.Errorf("invalid date range %q, expected %s", value, dateRangeFormatHint)
+	switch value {
+	case "today":
+		return today, today, nil
+	case "yesterday":
+		yesterday := GetPrevDayOf(today)
+		return yesterday, yesterday, nil
+	case "this-week", "last-week":
+		start := GetStartOfWeek(today, weekStart)
+		if value == "last-week" {
+			start = start.AddDate(0, 0, -7)
+		}
+		return start, start.AddDate(0, 0, 6), nil
+	case "this-month", "last-month":
+		start := time.Date(today.Year(), today.Month(), 1, 12, 0, 0, 0, today.Location())
+		if value == "last-month" {
+			start = start.AddDate(0, -1, 0)
+		}
+		return start, start.AddDate(0, 1, -1), nil
+	case "this-year", "last-year":
+		year := today.Year()
+		if value == "last-year" {
+			year--
+		}
+		return yearRange(year, today.Location())
+	}
+
+	if match := lastRangePattern.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil || n < 1 {
+			return time.Time{}, time.Time{}, invalid
+		}
+		var start time.Time
+		switch match[2] {
+		case "d":
+			start = today.AddDate(0, 0, -n)
+		case "w":
+			start = today.AddDate(0, 0, -7*n)
+		case "m":
+			start = addMonths(today, -n)
+		case "y":
+			start = addMonths(today, -12*n)
+		}
+		// last-30d covers 30 days including today
+		return GetNextDayOf(start), today, nil
+	}
+	if match := quarterPattern.FindStringSubmatch(value); match != nil {
+		year := today.Year()
+		if match[1] != "" {
+			year, _ = strconv.Atoi(match[1])
+		}
+		quarter, _ := strconv.Atoi(match[2])
+		start := time.Date(year, time.Month(quarter*3-2), 1, 12, 0, 0, 0, today.Location())
+		return start, start.AddDate(0, 3, -1), nil
+	}
+	if yearPattern.MatchString(value) {
+		year, _ := strconv.Atoi(value)
+		return yearRange(year, today.Location())
+	}
+	if monthPattern.MatchString(value) {
+		start, err := time.ParseInLocation("2006-01", value, today.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, invalid
+		}
+		start = GetNoonOf(start)
+		return start, start.AddDate(0, 1, -1), nil
+	}
+	day, err := time.ParseInLocation(time.DateOnly, value, today.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, invalid
+	}
+	day = GetNoonOf(day)
+	return day, day, nil
+}
+
+// addMonths moves day by months, clamped to the last day of the target month instead of
+// overflowing into the next one like time.AddDate does (march 31st minus a month is february 28th).
+func addMonths(day time.Time, months int) time.Time {
+	firstOfMonth := time.Date(day.Year(), day.Month()+time.Month(months), 1, day.Hour(), day.Minute(), 0, 0, day.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(day.Day(), lastDay)-1)
+}
+
+func yearRange(year int, location *time.Location) (time.Time, time.Time, error) {
+	start := time.Date(year, time.January, 1, 12, 0, 0, 0, location)
+	return start, start.AddDate(1, 0, -1), nil
+}
+
+// ParseFromTo turns --from and --to values into one range: from the start of from to the end of to.
+// Without to a single date runs up to today and any other range stands on its own, so --from q3 is all of q3.
+func ParseFromTo(from, to string, today time.Time, weekStart time.Weekday) (time.Time, time.Time, error) {
+	if strings.TrimSpace(from) == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to needs --from")
+	}
+	start, end, err := ParseDateRange(from, today, weekStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if strings.TrimSpace(to) != "" {
+		if _, end, err = ParseDateRange(to, today, weekStart); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	} else if _, err := time.Parse(time.DateOnly, strings.TrimSpace(from)); err == nil {
+		end = GetNoonOf(today)
+	}
+	if CompareDate(start, end) == -1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range starts on %s after it ends on %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	}
+	return start, end, nil
+}
+
+// RangesOverlap reports whether the days from start to end share at least one day with the days from from to to.
+func RangesOverlap(start, end, from, to time.Time) bool {
+	return CompareDate(start, to) >= 0 && CompareDate(from, end) >= 0
+}
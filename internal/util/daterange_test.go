@@ -0,0 +1,117 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateRange(t *testing.T) {
+	// a wednesday
+	today := time.Date(2026, 8, 19, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		value     string
+		weekStart time.Weekday
+		start     string
+		end       string
+	}{
+		{"2026-03-01", time.Monday, "2026-03-01", "2026-03-01"},
+		{" 2026-02 ", time.Monday, "2026-02-01", "2026-02-28"},
+		{"2024", time.Monday, "2024-01-01", "2024-12-31"},
+		{"today", time.Monday, "2026-08-19", "2026-08-19"},
+		{"yesterday", time.Monday, "2026-08-18", "2026-08-18"},
+		{"this-week", time.Monday, "2026-08-17", "2026-08-23"},
+		{"this-week", time.Sunday, "2026-08-16", "2026-08-22"},
+		{"last-week", time.Monday, "2026-08-10", "2026-08-16"},
+		{"this-month", time.Monday, "2026-08-01", "2026-08-31"},
+		{"last-month", time.Monday, "2026-07-01", "2026-07-31"},
+		{"this-year", time.Monday, "2026-01-01", "2026-12-31"},
+		{"last-year", time.Monday, "2025-01-01", "2025-12-31"},
+		{"last-30d", time.Monday, "2026-07-21", "2026-08-19"},
+		{"last-1d", time.Monday, "2026-08-19", "2026-08-19"},
+		{"last-2w", time.Monday, "2026-08-06", "2026-08-19"},
+		{"last-6m", time.Monday, "2026-02-20", "2026-08-19"},
+		{"last-1y", time.Monday, "2025-08-20", "2026-08-19"},
+		{"90d", time.Monday, "2026-05-22", "2026-08-19"},
+		{"6m", time.Monday, "2026-02-20", "2026-08-19"},
+		{"q1", time.Monday, "2026-01-01", "2026-03-31"},
+		{"Q3", time.Monday, "2026-07-01", "2026-09-30"},
+		{"2025-q4", time.Monday, "2025-10-01", "2025-12-31"},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseDateRange(tt.value, today, tt.weekStart)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.start, start.Format(time.DateOnly), tt.value)
+		assert.Equal(t, tt.end, end.Format(time.DateOnly), tt.value)
+		assert.Equal(t, 12, start.Hour(), tt.value)
+	}
+
+	for _, value := range []string{"", "last-0d", "0d", "last-30x", "q5", "2026-13", "2026-02-30", "next-week", "26"} {
+		_, _, err := ParseDateRange(value, today, time.Monday)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseDateRange_MonthEnds(t *testing.T) {
+	tests := []struct {
+		today string
+		value string
+		start string
+	}{
+		// february has no 31st, the month before march 31st ends on the 28th
+		{"2026-03-31", "last-1m", "2026-03-01"},
+		{"2026-05-31", "last-3m", "2026-03-01"},
+		{"2024-03-31", "last-1m", "2024-03-01"},
+		{"2026-12-31", "last-10m", "2026-03-01"},
+		{"2024-02-29", "last-1y", "2023-03-01"},
+		{"2026-01-31", "last-2m", "2025-12-01"},
+	}
+	for _, tt := range tests {
+		today, err := time.ParseInLocation(time.DateOnly, tt.today, time.Local)
+		require.NoError(t, err)
+		start, end, err := ParseDateRange(tt.value, today, time.Monday)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.start, start.Format(time.DateOnly), tt.today+" "+tt.value)
+		assert.Equal(t, tt.today, end.Format(time.DateOnly), tt.today+" "+tt.value)
+	}
+}
+
+func TestParseFromTo(t *testing.T) {
+	today := time.Date(2026, 8, 19, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		from  string
+		to    string
+		start string
+		end   string
+	}{
+		{"2026-03-01", "", "2026-03-01", "2026-08-19"},
+		{"2026-03-01", "2026-03-31", "2026-03-01", "2026-03-31"},
+		{"q1", "", "2026-01-01", "2026-03-31"},
+		{"q1", "q2", "2026-01-01", "2026-06-30"},
+		{"2025", "this-month", "2025-01-01", "2026-08-31"},
+		{"last-30d", "", "2026-07-21", "2026-08-19"},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseFromTo(tt.from, tt.to, today, time.Monday)
+		require.NoError(t, err, tt.from)
+		assert.Equal(t, tt.start, start.Format(time.DateOnly), tt.from)
+		assert.Equal(t, tt.end, end.Format(time.DateOnly), tt.from)
+	}
+
+	_, _, err := ParseFromTo("", "2026-03-01", today, time.Monday)
+	assert.Error(t, err)
+	_, _, err = ParseFromTo("2026-03-01", "2026-02-01", today, time.Monday)
+	assert.Error(t, err)
+	_, _, err = ParseFromTo("2026-03-01", "soon", today, time.Monday)
+	assert.Error(t, err)
+}
+
+func TestRangesOverlap(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.Local) }
+	assert.True(t, RangesOverlap(day(1), day(10), day(10), day(20)))
+	assert.True(t, RangesOverlap(day(5), day(6), day(1), day(20)))
+	assert.False(t, RangesOverlap(day(1), day(9), day(10), day(20)))
+	assert.False(t, RangesOverlap(day(21), day(25), day(10), day(20)))
+}
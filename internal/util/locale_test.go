@@ -0,0 +1,76 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLocale(t *testing.T) {
+	for _, name := range []string{"", "en", "EN", "en-US", "en_GB.UTF-8"} {
+		locale, err := GetLocale(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "January", locale.MonthName(time.January), name)
+	}
+	locale, err := GetLocale("de_DE.UTF-8")
+	require.NoError(t, err)
+	assert.Equal(t, "März", locale.MonthName(time.March))
+	assert.Equal(t, "Mo", locale.ShortWeekdays[time.Monday])
+
+	_, err = GetLocale("xx")
+	assert.Error(t, err)
+}
+
+func TestLocale_ParseMonth(t *testing.T) {
+	tests := []struct {
+		locale string
+		name   string
+		month  time.Month
+		ok     bool
+	}{
+		{"en", "january", time.January, true},
+		{"en", "Sep", time.September, true},
+		{"de", "März", time.March, true},
+		{"de", "okt", time.October, true},
+		{"fr", "Février", time.February, true},
+		{"fr", "août", time.August, true},
+		{"es", "diciembre", time.December, true},
+		{"nl", "mrt", time.March, true},
+		{"de", "october", 0, false},
+		{"en", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.name, func(t *testing.T) {
+			locale, err := GetLocale(tt.locale)
+			require.NoError(t, err)
+			month, ok := locale.ParseMonth(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.month, month)
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	// a wednesday
+	day := time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		setting string
+		offset  int
+		start   string
+	}{
+		{"", 2, "2026-03-02"},
+		{"monday", 2, "2026-03-02"},
+		{"Sunday", 3, "2026-03-01"},
+		{"saturday", 4, "2026-02-28"},
+	}
+	for _, tt := range tests {
+		weekStart, err := ParseWeekStart(tt.setting)
+		require.NoError(t, err, tt.setting)
+		assert.Equal(t, tt.offset, GetWeekdayOffset(day, weekStart), tt.setting)
+		assert.Equal(t, tt.start, GetStartOfWeek(day, weekStart).Format(time.DateOnly), tt.setting)
+	}
+	_, err := ParseWeekStart("friday")
+	assert.Error(t, err)
+}
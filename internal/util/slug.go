@@ -0,0 +1,38 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// MaxHabitNameWidth is how wide a habit's display name may be in the terminal.
+const MaxHabitNameWidth = 20
+
+// DisplayWidth is the number of terminal cells s takes up, counting grapheme clusters
+// so that wide emoji take two cells and combining marks none.
+func DisplayWidth(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// Slugify normalizes a habit display name to the slug commands match on:
+// lowercase letters and digits, with runs of spaces, '-' and '_' collapsed into a single '-'.
+// Everything else (emoji, punctuation) is dropped, so the slug can be empty.
+func Slugify(name string) string {
+	var slug strings.Builder
+	pendingSeparator := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			if pendingSeparator && slug.Len() > 0 {
+				slug.WriteRune('-')
+			}
+			pendingSeparator = false
+			slug.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			pendingSeparator = true
+		}
+	}
+	return slug.String()
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "running", want: "running"},
+		{name: "Morning Walk", want: "morning-walk"},
+		{name: "  read  30_min ", want: "read-30-min"},
+		{name: "Café", want: "café"},
+		{name: "🏃 Run", want: "run"},
+		{name: "no-sugar!", want: "no-sugar"},
+		{name: "🏃", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Slugify(tt.name))
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "running", want: 7},
+		{name: "Café", want: 4},
+		// e followed by a combining acute accent
+		{name: "Café", want: 4},
+		{name: "🏃 Run", want: 6},
+		{name: "👨‍👩‍👧", want: 2},
+		{name: "読書", want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DisplayWidth(tt.name))
+		})
+	}
+}
@@ -1,13 +1,6 @@
 package util
 
-import (
-	"fmt"
-	"log/slog"
-	"os"
-	"sync"
-
-	"github.com/Atharva21/streakr/internal/shutdown"
-)
+import "sync"
 
 var (
 	logFileAbsolutePath string
@@ -20,15 +13,7 @@ func BootstrapUtil(logLocation string) {
 	})
 }
 
-func ErrorAndExitGeneric(err error) {
-	if err != nil {
-		slog.Error(err.Error())
-	}
-	fmt.Fprintf(os.Stderr, "An unexpected error occurred. Please check the logs at %s for more details.\n", logFileAbsolutePath)
-	shutdown.GracefulShutdown(1)
-}
-
-func ErrorAndExit(msg string) {
-	fmt.Fprintln(os.Stderr, msg)
-	shutdown.GracefulShutdown(1)
+// LogFilePath is where errors are logged, empty before BootstrapUtil.
+func LogFilePath() string {
+	return logFileAbsolutePath
 }
@@ -7,7 +7,6 @@ import (
 	"syscall"
 
 	"github.com/Atharva21/streakr/cmd"
-	_ "github.com/Atharva21/streakr/internal/streakr"
 )
 
 func main() {